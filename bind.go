@@ -0,0 +1,308 @@
+package jumper
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bindSources is ordered list of struct tags understood by BindTo and BindOf.
+var bindSources = []string{"path", "query", "form", "header", "file"}
+
+var (
+	fileType           = reflect.TypeOf(&File{})
+	durationType       = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// BindTo fill new T from every request source and validate it, see BindOf.
+func BindTo[T any](r *Request) (T, error) {
+	var en T
+	err := BindOf(r, &en)
+	return en, err
+}
+
+// BindOf fill en from every request source.
+// Body params are bound through `json` tag first, then fields tagged with
+// `path`, `query`, `form`, `header` or `file` are filled from named segment,
+// query string, form value, header or uploaded file respectively.
+// Conversion and `validate` failures are returned as ValidationErrors, bound files are closed when error is returned.
+func BindOf[T any](r *Request, en *T) error {
+	params := bodyParams(r.params, reflect.TypeOf(en).Elem())
+	jsonString, _ := json.Marshal(params)
+	err := json.Unmarshal(jsonString, en)
+
+	var errs ValidationErrors
+	if err != nil {
+		var ok bool
+		if errs, ok = bindErrors(err, params); !ok {
+			return err
+		}
+	}
+	var files []*File
+	r.bindStruct(reflect.ValueOf(en).Elem(), "", &errs, &files)
+	if len(errs) == 0 {
+		err = validateBound(en, params, nil)
+	} else {
+		err = validateBound(en, params, errs)
+	}
+	if err != nil {
+		for _, f := range files {
+			f.f.Close()
+		}
+	}
+	return err
+}
+
+// bodyParams exclude params that would land on source tagged fields, json matching is case-insensitive.
+func bodyParams(params Params, t reflect.Type) Params {
+	if t.Kind() != reflect.Struct {
+		return params
+	}
+	tagged := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		for _, s := range bindSources {
+			if _, ok := sf.Tag.Lookup(s); ok {
+				name, _ := fieldName(sf)
+				tagged[strings.ToLower(name)] = true
+				break
+			}
+		}
+	}
+	if len(tagged) == 0 {
+		return params
+	}
+	filtered := Params{}
+	for k, v := range params {
+		if !tagged[strings.ToLower(k)] {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// bindStruct fill source tagged fields of v, errors are reported under the same path as Validate, see inputName.
+// Opened files are collected into files.
+func (r *Request) bindStruct(v reflect.Value, path string, errs *ValidationErrors, files *[]*File) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		name, ok := inputName(sf)
+		if !ok {
+			continue
+		}
+		fieldPath := path
+		if !sf.Anonymous || name != "" {
+			fieldPath = joinPath(path, name)
+		}
+
+		source, key := bindSource(sf)
+		if source == "" {
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				r.bindStruct(fv, fieldPath, errs, files)
+			} else if fv.Kind() == reflect.Pointer && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct && fv.Elem().Type() != timeType {
+				r.bindStruct(fv.Elem(), fieldPath, errs, files)
+			}
+			continue
+		}
+
+		if source == "file" {
+			if err := r.bindFile(fv, key, files); err != nil {
+				*errs = append(*errs, newFieldError(fieldPath, "type", fv.Type().String(), nil, err.Error()))
+			}
+			continue
+		}
+
+		values := r.sourceValues(source, key)
+		if len(values) == 0 {
+			continue
+		}
+		if err := r.setValues(fv, values); err != nil {
+			*errs = append(*errs, newFieldError(fieldPath, "type", fv.Type().String(), values[0], "{field} must be of type {param}"))
+		}
+	}
+}
+
+// bindSource return first source tag of field and its key, key default to field name.
+func bindSource(sf reflect.StructField) (source string, key string) {
+	for _, s := range bindSources {
+		if k, ok := sf.Tag.Lookup(s); ok && k != "-" {
+			if k == "" {
+				k = sf.Name
+			}
+			return s, k
+		}
+	}
+	return "", ""
+}
+
+// inputName is name of field in error path, source key for source tagged field and json name otherwise,
+// so client can map the error back to its input.
+func inputName(sf reflect.StructField) (string, bool) {
+	if _, key := bindSource(sf); key != "" {
+		return key, true
+	}
+	return fieldName(sf)
+}
+
+func (r *Request) sourceValues(source, key string) []string {
+	switch source {
+	case "path":
+		if v, ok := r.segments[key]; ok {
+			return []string{v}
+		}
+	case "query":
+		return r.query[key]
+	case "form":
+		return r.form[key]
+	case "header":
+		return r.header.Values(key)
+	}
+	return nil
+}
+
+// bindFile open uploaded files of key into v and append them to opened, files opened before failure are closed.
+func (r *Request) bindFile(v reflect.Value, key string, opened *[]*File) error {
+	if r.files[key] == nil {
+		return nil
+	}
+	var headers []*multipart.FileHeader
+	switch fh := r.files[key].(type) {
+	case *multipart.FileHeader:
+		headers = []*multipart.FileHeader{fh}
+	case []*multipart.FileHeader:
+		headers = fh
+	}
+
+	switch v.Type() {
+	case fileType:
+		f, err := headers[0].Open()
+		if err != nil {
+			return err
+		}
+		file := &File{f: f, fh: headers[0]}
+		v.Set(reflect.ValueOf(file))
+		*opened = append(*opened, file)
+	case reflect.SliceOf(fileType):
+		files := make([]*File, 0, len(headers))
+		for _, h := range headers {
+			f, err := h.Open()
+			if err != nil {
+				for _, file := range files {
+					file.f.Close()
+				}
+				return err
+			}
+			files = append(files, &File{f: f, fh: h})
+		}
+		v.Set(reflect.ValueOf(files))
+		*opened = append(*opened, files...)
+	default:
+		return fmt.Errorf("%s must be *jumper.File or []*jumper.File", key)
+	}
+	return nil
+}
+
+// setValues convert string values into v, slices receive every value, other kinds only the first.
+// Pointer is allocated and filled the same way, so *[]string receive every value too.
+func (r *Request) setValues(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := r.setValues(elem.Elem(), values); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 && !v.Addr().Type().Implements(textUnmarshalerTyp) {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := r.setValues(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+	return r.setValue(v, values[0])
+}
+
+func (r *Request) setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Pointer {
+		elem := reflect.New(v.Type().Elem())
+		if err := r.setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		t, err := r.parseTime(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.Addr().Type().Implements(textUnmarshalerTyp) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(value))
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(value))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
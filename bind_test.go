@@ -0,0 +1,95 @@
+package jumper
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBindErrorPaths(t *testing.T) {
+	type search struct {
+		Page   int    `query:"page" validate:"min=1"`
+		Tenant string `header:"X-Tenant" validate:"required"`
+		Inner  struct {
+			Q int `query:"q"`
+		}
+	}
+	req, err := ParseRequest(httptest.NewRequest("GET", "/?page=x&q=y", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = BindTo[search](req)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("BindTo() = %v, want ValidationErrors", err)
+	}
+	got := map[string]string{}
+	for _, e := range errs {
+		got[e.Field] = e.Rule
+	}
+	want := map[string]string{"page": "type", "Inner.q": "type", "X-Tenant": "required"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BindTo() errors = %v, want %v", got, want)
+	}
+}
+
+func TestBindSlices(t *testing.T) {
+	type search struct {
+		Tags   *[]string  `query:"tag"`
+		Groups [][]string `query:"group"`
+		IDs    []*int     `query:"id"`
+	}
+	req, err := ParseRequest(httptest.NewRequest("GET", "/?tag=a&tag=b&group=x&group=y&id=1&id=2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := BindTo[search](req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Tags == nil || !reflect.DeepEqual(*s.Tags, []string{"a", "b"}) {
+		t.Errorf("Tags = %v, want [a b]", s.Tags)
+	}
+	if !reflect.DeepEqual(s.Groups, [][]string{{"x"}, {"y"}}) {
+		t.Errorf("Groups = %v, want [[x] [y]]", s.Groups)
+	}
+	if len(s.IDs) != 2 || *s.IDs[0] != 1 || *s.IDs[1] != 2 {
+		t.Errorf("IDs = %v, want [1 2]", s.IDs)
+	}
+}
+
+func TestBindClosesFilesOnError(t *testing.T) {
+	type upload struct {
+		Page int     `query:"page"`
+		Docs []*File `file:"doc"`
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		fw, _ := w.CreateFormFile("doc", name)
+		fw.Write(bytes.Repeat([]byte("x"), 64<<10)) // larger than DefaultMaxMultipartMemory, so kept on disk
+	}
+	w.Close()
+	r := httptest.NewRequest("POST", "/?page=x", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	req, err := ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := BindTo[upload](req)
+	if err == nil {
+		t.Fatal("BindTo() succeeded, want type error")
+	}
+	if len(u.Docs) != 2 {
+		t.Fatalf("Docs = %d files, want 2", len(u.Docs))
+	}
+	for _, f := range u.Docs {
+		if _, err := f.GetFile().Read(make([]byte, 1)); !errors.Is(err, os.ErrClosed) {
+			t.Errorf("Read() after failed bind = %v, want os.ErrClosed", err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Command jumper-status generate typed Go statuses and reference documentation from status catalog file.
+//
+//	//go:generate go run git.verzth.work/go/jumper/v2/cmd/jumper-status -in statuses.yaml -out statuses_gen.go -md STATUSES.md
+//
+// Generated variables are registered into jumper.DefaultCatalog, or into catalog named by -catalog,
+// so duplicated number or code still fail at startup.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"git.verzth.work/go/jumper/v2"
+)
+
+type entry struct {
+	jumper.Status
+	Ident string
+}
+
+func main() {
+	in := flag.String("in", "", "status catalog file, .json, .yaml or .yml (required)")
+	out := flag.String("out", "", "generated Go file, empty means stdout")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of generated Go file")
+	catalog := flag.String("catalog", "", "declare catalog variable of this name instead of using jumper.DefaultCatalog")
+	md := flag.String("md", "", "write Markdown reference to this file")
+	html := flag.String("html", "", "write HTML reference to this file")
+	flag.Parse()
+
+	if err := run(*in, *out, *pkg, *catalog, *md, *html); err != nil {
+		fmt.Fprintln(os.Stderr, "jumper-status:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg, catalog, md, html string) error {
+	if in == "" {
+		return fmt.Errorf("-in is required")
+	}
+	if pkg == "" {
+		pkg = "status"
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	statuses, err := jumper.ParseStatuses(data, strings.TrimPrefix(filepath.Ext(in), "."))
+	if err != nil {
+		return err
+	}
+	if err = jumper.NewCatalog().Register(statuses...); err != nil {
+		return err
+	}
+	entries, err := identify(statuses)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(filepath.Base(in), pkg, catalog, entries)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(out, src, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	if md != "" {
+		if err = os.WriteFile(md, markdown(entries), 0644); err != nil {
+			return err
+		}
+	}
+	if html != "" {
+		var buf bytes.Buffer
+		if err = htmlTemplate.Execute(&buf, entries); err != nil {
+			return err
+		}
+		if err = os.WriteFile(html, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// identify give every status unique exported Go identifier.
+func identify(statuses []jumper.Status) ([]entry, error) {
+	entries := make([]entry, len(statuses))
+	seen := map[string]string{}
+	for i, s := range statuses {
+		ident := s.Name
+		if ident == "" {
+			ident = camelCase(s.Code)
+		}
+		if !token.IsIdentifier(ident) || !token.IsExported(ident) {
+			return nil, fmt.Errorf("status %s: %q is not exported Go identifier, set name", s.Code, ident)
+		}
+		if prev, ok := seen[ident]; ok {
+			return nil, fmt.Errorf("status %s: identifier %s is used by %s, set name", s.Code, ident, prev)
+		}
+		seen[ident] = s.Code
+		entries[i] = entry{Status: s, Ident: ident}
+	}
+	return entries, nil
+}
+
+// camelCase turn "USER_NOT_FOUND" or "user-not-found" into "UserNotFound".
+func camelCase(code string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(code, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+	ident := b.String()
+	if ident != "" && unicode.IsDigit(rune(ident[0])) {
+		ident = "Status" + ident
+	}
+	return ident
+}
+
+func generate(source, pkg, catalog string, entries []entry) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by jumper-status from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"git.verzth.work/go/jumper/v2\"\n\n")
+
+	register := "jumper.MustRegisterStatus"
+	if catalog != "" {
+		fmt.Fprintf(&b, "// %s hold statuses generated from %s.\n", catalog, source)
+		fmt.Fprintf(&b, "var %s = jumper.NewCatalog()\n\n", catalog)
+		register = catalog + ".MustRegister"
+	}
+
+	b.WriteString("var (\n")
+	for _, e := range entries {
+		comment := e.Description
+		if comment == "" {
+			comment = e.Message
+		}
+		fmt.Fprintf(&b, "\t// %s is %s %s", e.Ident, e.Number, e.Code)
+		if comment != "" {
+			fmt.Fprintf(&b, ", %s", strings.ReplaceAll(comment, "\n", " "))
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "\t%s = %s(jumper.Status{HttpStatusCode: %d, Success: %t, Number: %s, Code: %s, Message: %s",
+			e.Ident, register, e.HttpStatusCode, e.Success, strconv.Quote(e.Number), strconv.Quote(e.Code), strconv.Quote(e.Message))
+		if e.Name != "" {
+			fmt.Fprintf(&b, ", Name: %s", strconv.Quote(e.Name))
+		}
+		if e.Description != "" {
+			fmt.Fprintf(&b, ", Description: %s", strconv.Quote(e.Description))
+		}
+		b.WriteString("})\n")
+	}
+	b.WriteString(")\n")
+	return format.Source(b.Bytes())
+}
+
+func markdown(entries []entry) []byte {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	var b bytes.Buffer
+	b.WriteString("# Status Reference\n\n")
+	b.WriteString("| Number | Code | HTTP | Status | Message | Description |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| `%s` | `%s` | %d | %s | %s | %s |\n",
+			e.Number, e.Code, httpCode(e.HttpStatusCode), result(e.Success), cell.Replace(e.Message), cell.Replace(e.Description))
+	}
+	return b.Bytes()
+}
+
+func httpCode(code int) int {
+	if code == 0 {
+		return 200
+	}
+	return code
+}
+
+func result(success bool) string {
+	if success {
+		return "1 (success)"
+	}
+	return "0 (failed)"
+}
+
+var htmlTemplate = template.Must(template.New("statuses").Funcs(template.FuncMap{
+	"httpCode": httpCode,
+	"result":   result,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Status Reference</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Status Reference</h1>
+<table>
+<tr><th>Number</th><th>Code</th><th>HTTP</th><th>Status</th><th>Message</th><th>Description</th></tr>
+{{- range .}}
+<tr id="{{.Code}}"><td><code>{{.Number}}</code></td><td><code>{{.Code}}</code></td><td>{{httpCode .HttpStatusCode}}</td><td>{{result .Success}}</td><td>{{.Message}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
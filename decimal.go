@@ -0,0 +1,360 @@
+package jumper
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode decide how Decimal.Round and Decimal.Div drop digits.
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // 2.5 => 3, -2.5 => -3
+	RoundHalfEven                     // 2.5 => 2, 3.5 => 4
+	RoundHalfDown                     // 2.5 => 2, 2.6 => 3
+	RoundUp                           // away from zero
+	RoundDown                         // toward zero
+	RoundCeiling                      // toward positive infinity
+	RoundFloor                        // toward negative infinity
+)
+
+var (
+	ErrDivisionByZero = errors.New("decimal division by zero")
+	ErrDecimalRange   = errors.New("decimal scale out of range")
+)
+
+// MaxDecimalScale bound scale of parsed Decimal both ways, so literal like "1e50000000" from request
+// is refused instead of building huge number.
+const MaxDecimalScale = 4096
+
+// Decimal is arbitrary precision decimal number, use it for money instead of float64.
+// Value is unscaled * 10^-scale, so "12.50" keep its scale of 2 through JSON and database.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+var bigTen = big.NewInt(10)
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// NewDecimal create decimal of unscaled * 10^-scale, e.g. NewDecimal(1250, 2) is 12.50.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{unscaled: new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))}
+	}
+	return Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal parse decimal literal like "12.50", "-0.5" or "1.25e3".
+// Literal whose scale exceed MaxDecimalScale either way fail with ErrDecimalRange.
+func ParseDecimal(s string) (Decimal, error) {
+	literal := strings.TrimSpace(s)
+	mantissa, exponent := literal, int64(0)
+	if i := strings.IndexAny(literal, "eE"); i != -1 {
+		var err error
+		mantissa = literal[:i]
+		if exponent, err = strconv.ParseInt(literal[i+1:], 10, 32); err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+	}
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale > MaxDecimalScale || scale < -MaxDecimalScale {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrDecimalRange, s)
+	}
+	unscaled, _ := new(big.Int).SetString(sign+digits, 10)
+	if scale < 0 {
+		return Decimal{unscaled: unscaled.Mul(unscaled, pow10(int32(-scale)))}, nil
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is ParseDecimal that panic on invalid literal.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat convert float using its shortest representation, 0.1 become exactly 0.1.
+func DecimalFromFloat(f float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// Scale return number of digits after decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// rescale return unscaled value of d on bigger scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	return new(big.Int).Mul(d.value(), pow10(scale-d.scale))
+}
+
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	if a.scale > b.scale {
+		return a.value(), b.rescale(a.scale), a.scale
+	}
+	return a.rescale(b.scale), b.value(), b.scale
+}
+
+// Cmp compare value regardless of scale, 1.5 equal 1.50.
+func (d Decimal) Cmp(o Decimal) int {
+	a, b, _ := align(d, o)
+	return a.Cmp(b)
+}
+
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	a, b, scale := align(d, o)
+	return Decimal{unscaled: new(big.Int).Add(a, b), scale: scale}
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	a, b, scale := align(d, o)
+	return Decimal{unscaled: new(big.Int).Sub(a, b), scale: scale}
+}
+
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), o.value()), scale: d.scale + o.scale}
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// Div divide d by o into given scale, dropped digits are rounded with mode.
+func (d Decimal) Div(o Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	num, den := new(big.Int).Set(d.value()), new(big.Int).Set(o.value())
+	if shift := scale + o.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	return Decimal{unscaled: roundQuo(num, den, mode), scale: scale}, nil
+}
+
+// Round set scale of d, dropped digits are rounded with mode and missing digits are filled with zero.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	if scale >= d.scale {
+		return Decimal{unscaled: d.rescale(scale), scale: scale}
+	}
+	return Decimal{unscaled: roundQuo(d.value(), pow10(d.scale-scale), mode), scale: scale}
+}
+
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := num.Sign() * den.Sign()
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = cmp >= 0
+	case RoundHalfDown:
+		away = cmp > 0
+	case RoundHalfEven:
+		away = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+	case RoundUp:
+		away = true
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// String return literal keeping scale, e.g. "12.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.value()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed return literal rounded half up into given scale.
+func (d Decimal) StringFixed(scale int32) string {
+	return d.Round(scale, RoundHalfUp).String()
+}
+
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.value(), pow10(d.scale))
+}
+
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Int64 return integer part of d.
+func (d Decimal) Int64() int64 {
+	return d.Round(0, RoundDown).value().Int64()
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accept JSON number or string literal, null leave d untouched.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if d == nil {
+		return errors.New("null point exception")
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	v, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(data []byte) error {
+	v, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case string:
+		*d, err = ParseDecimal(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+	case float64:
+		*d = DecimalFromFloat(v)
+	default:
+		err = errors.New("Invalid Scan Source")
+	}
+	return err
+}
+
+// Decimal return the literal as Decimal without precision loss.
+func (n ExactNumber) Decimal() (Decimal, error) {
+	return ParseDecimal(n.String())
+}
+
+// GetDecimalE get key value as Decimal, error is *ParamError.
+// JSON number and numeric string are parsed exactly, float64 use its shortest representation.
+func (r *Request) GetDecimalE(key string) (Decimal, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return Decimal{}, err
+	}
+	switch v := param.(type) {
+	case json.Number:
+		if d, err := ParseDecimal(v.String()); err == nil {
+			return d, nil
+		}
+		return Decimal{}, r.paramError(key, param, ErrParamSyntax)
+	case string:
+		if d, err := ParseDecimal(v); err == nil {
+			return d, nil
+		}
+		return Decimal{}, r.paramError(key, param, ErrParamSyntax)
+	case float64:
+		return DecimalFromFloat(v), nil
+	case int:
+		return NewDecimal(int64(v), 0), nil
+	}
+	return Decimal{}, r.paramError(key, param, ErrParamType)
+}
+
+func (r *Request) GetDecimalPtr(key string) *Decimal {
+	if v, err := r.GetDecimalE(key); err == nil {
+		return &v
+	}
+	return nil
+}
+
+func (r *Request) GetDecimal(key string) Decimal {
+	v, _ := r.GetDecimalE(key)
+	return v
+}
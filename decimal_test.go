@@ -0,0 +1,103 @@
+package jumper
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for literal, want := range map[string]string{
+		"12.50":   "12.50",
+		"-0.5":    "-0.5",
+		"+7":      "7",
+		"1.25e3":  "1250",
+		"1.25e-3": "0.00125",
+		"0.5e-2":  "0.005",
+	} {
+		d, err := ParseDecimal(literal)
+		if err != nil || d.String() != want {
+			t.Errorf("ParseDecimal(%q) = %v, %v, want %s", literal, d, err, want)
+		}
+	}
+	for _, literal := range []string{"", "abc", "1.2.3", "1e", "--1"} {
+		if _, err := ParseDecimal(literal); err == nil {
+			t.Errorf("ParseDecimal(%q) succeeded, want error", literal)
+		}
+	}
+}
+
+func TestParseDecimalScaleBound(t *testing.T) {
+	for _, literal := range []string{"1e50000000", "0.5e-2147483647", "1e-4097", "1e4097"} {
+		start := time.Now()
+		_, err := ParseDecimal(literal)
+		if !errors.Is(err, ErrDecimalRange) {
+			t.Errorf("ParseDecimal(%q) = %v, want ErrDecimalRange", literal, err)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("ParseDecimal(%q) took %s", literal, elapsed)
+		}
+	}
+	if d, err := ParseDecimal("1e4096"); err != nil || d.Scale() != 0 {
+		t.Errorf("ParseDecimal(1e4096) = %v, want it accepted", err)
+	}
+
+	var v struct {
+		Price Decimal `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":1e50000000}`), &v); !errors.Is(err, ErrDecimalRange) {
+		t.Errorf("Unmarshal() = %v, want ErrDecimalRange", err)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	price := MustParseDecimal("12.50")
+	if got := price.Mul(NewDecimal(3, 0)).String(); got != "37.50" {
+		t.Errorf("12.50 * 3 = %s", got)
+	}
+	if got := price.Add(MustParseDecimal("0.005")).String(); got != "12.505" {
+		t.Errorf("12.50 + 0.005 = %s", got)
+	}
+	share, err := MustParseDecimal("10").Div(MustParseDecimal("3"), 2, RoundHalfEven)
+	if err != nil || share.String() != "3.33" {
+		t.Errorf("10 / 3 = %v, %v", share, err)
+	}
+	if _, err = price.Div(Decimal{}, 2, RoundHalfUp); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Div(0) = %v, want ErrDivisionByZero", err)
+	}
+	for _, c := range []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.5", RoundHalfUp, "3"},
+		{"-2.5", RoundHalfUp, "-3"},
+		{"2.5", RoundHalfEven, "2"},
+		{"3.5", RoundHalfEven, "4"},
+		{"2.5", RoundHalfDown, "2"},
+		{"2.1", RoundUp, "3"},
+		{"2.9", RoundDown, "2"},
+		{"-2.1", RoundCeiling, "-2"},
+		{"-2.1", RoundFloor, "-3"},
+	} {
+		if got := MustParseDecimal(c.value).Round(0, c.mode).String(); got != c.want {
+			t.Errorf("Round(%s, %d) = %s, want %s", c.value, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal  `json:"a"`
+		B Decimal  `json:"b"`
+		C *Decimal `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":12.50,"b":"0.10","c":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil || string(out) != `{"a":12.50,"b":0.10,"c":null}` {
+		t.Errorf("Marshal() = %s, %v", out, err)
+	}
+}
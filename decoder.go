@@ -0,0 +1,308 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder decode request body into params.
+type Decoder func(body io.Reader, params Params) error
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{
+		"application/json":        decodeJSON,
+		"application/xml":         decodeXML,
+		"text/xml":                decodeXML,
+		"application/yaml":        decodeYAML,
+		"application/x-yaml":      decodeYAML,
+		"text/yaml":               decodeYAML,
+		"application/toml":        decodeTOML,
+		"application/cbor":        decodeCBOR,
+		"application/msgpack":     decodeMsgpack,
+		"application/x-msgpack":   decodeMsgpack,
+		"application/vnd.msgpack": decodeMsgpack,
+	}
+)
+
+// RegisterDecoder register body decoder for media type, replacing existing one.
+// Media type with structured syntax suffix, e.g. application/vnd.api+json, fallback to decoder of its suffix.
+func RegisterDecoder(mediaType string, fn Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(mediaType)] = fn
+}
+
+// lookupDecoder find decoder of media type, also return media type the decoder registered for.
+func lookupDecoder(mediaType string) (Decoder, string) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	if fn, ok := decoders[mediaType]; ok {
+		return fn, mediaType
+	}
+	if i := strings.LastIndex(mediaType, "+"); i != -1 {
+		suffixType := "application/" + mediaType[i+1:]
+		if fn, ok := decoders[suffixType]; ok {
+			return fn, suffixType
+		}
+	}
+	return nil, ""
+}
+
+func mediaTypeOf(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// decodeJSON decode numbers as json.Number so integers above 2^53 stay exact.
+func decodeJSON(body io.Reader, params Params) error {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	return dec.Decode(&params)
+}
+
+// floatParams replace json.Number in params by float64, see FloatNumbers.
+func floatParams(params Params) {
+	for k, v := range params {
+		params[k] = floatNumbers(v)
+	}
+}
+
+func floatNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = floatNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range t {
+			t[i] = floatNumbers(item)
+		}
+	}
+	return v
+}
+
+func unmarshalNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// decodeXML put children of root element into params. Element with only text become string,
+// element with attributes or children become map with attributes prefixed by '@' and text on '#text',
+// repeated elements become array.
+func decodeXML(body io.Reader, params Params) error {
+	dec := xml.NewDecoder(body)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			root, err := decodeXMLElement(dec, start)
+			if err != nil {
+				return err
+			}
+			if m, ok := root.(map[string]interface{}); ok {
+				for k, v := range m {
+					params[k] = v
+				}
+			} else {
+				params[start.Name.Local] = root
+			}
+			return nil
+		}
+	}
+}
+
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	node := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		node["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	hasChild := false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			hasChild = true
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := node[name].(type) {
+			case nil:
+				node[name] = child
+			case []interface{}:
+				node[name] = append(existing, child)
+			default:
+				node[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if !hasChild && len(node) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				node["#text"] = content
+			}
+			return node, nil
+		}
+	}
+}
+
+var cborDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]interface{}{}),
+}.DecMode()
+
+func decodeYAML(body io.Reader, params Params) error {
+	var v interface{}
+	if err := yaml.NewDecoder(body).Decode(&v); err != nil {
+		return err
+	}
+	return mergeParams(params, v)
+}
+
+func decodeTOML(body io.Reader, params Params) error {
+	var v map[string]interface{}
+	if err := toml.NewDecoder(body).Decode(&v); err != nil {
+		return err
+	}
+	return mergeParams(params, v)
+}
+
+func decodeCBOR(body io.Reader, params Params) error {
+	var v interface{}
+	if err := cborDecMode.NewDecoder(body).Decode(&v); err != nil {
+		return err
+	}
+	return mergeParams(params, v)
+}
+
+func decodeMsgpack(body io.Reader, params Params) error {
+	v, err := msgpack.NewDecoder(body).DecodeInterface()
+	if err != nil {
+		return err
+	}
+	return mergeParams(params, v)
+}
+
+// mergeParams normalize decoded document and put it into params, document must be an object.
+func mergeParams(params Params, v interface{}) error {
+	m, ok := normalize(v).(map[string]interface{})
+	if !ok {
+		if v == nil {
+			return nil
+		}
+		return errors.New("body must be an object")
+	}
+	for k, val := range m {
+		params[k] = val
+	}
+	return nil
+}
+
+// normalize convert decoded value into types produced by JSON decoder,
+// numbers become json.Number, binary become base64 string and time become RFC3339 string.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil, string, bool, json.Number:
+		return t
+	case float64:
+		return json.Number(strconv.FormatFloat(t, 'g', -1, 64))
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalize(val)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalize(val)
+		}
+		return t
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case *big.Int:
+		return json.Number(t.String())
+	case big.Int:
+		return json.Number(t.String())
+	case cbor.Tag:
+		return normalize(t.Content)
+	case encoding.TextMarshaler:
+		b, err := t.MarshalText()
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return json.Number(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32:
+		return json.Number(strconv.FormatFloat(rv.Float(), 'g', -1, 32))
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = normalize(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value().Interface())
+		}
+		return m
+	}
+	return v
+}
@@ -0,0 +1,145 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func parseBody(t *testing.T, contentType string, body []byte) (*Request, error) {
+	t.Helper()
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return ParseRequest(r)
+}
+
+func TestDecoders(t *testing.T) {
+	doc := map[string]interface{}{"name": "jo", "age": 30, "tags": []interface{}{"a", "b"}, "ok": true}
+	cborBody, _ := cbor.Marshal(doc)
+	msgpackBody, _ := msgpack.Marshal(doc)
+	const want = `{"age":30,"name":"jo","ok":true,"tags":["a","b"]}`
+
+	for _, c := range []struct {
+		contentType string
+		body        []byte
+	}{
+		{"application/json", []byte(`{"name":"jo","age":30,"tags":["a","b"],"ok":true}`)},
+		{"application/vnd.api+json; charset=utf-8", []byte(`{"name":"jo","age":30,"tags":["a","b"],"ok":true}`)},
+		{"application/yaml", []byte("name: jo\nage: 30\ntags: [a, b]\nok: true\n")},
+		{"text/yaml", []byte("name: jo\nage: 30\ntags:\n  - a\n  - b\nok: true\n")},
+		{"application/toml", []byte("name = \"jo\"\nage = 30\ntags = [\"a\", \"b\"]\nok = true\n")},
+		{"application/cbor", cborBody},
+		{"application/msgpack", msgpackBody},
+	} {
+		req, err := parseBody(t, c.contentType, c.body)
+		if err != nil {
+			t.Errorf("%s: ParseRequest() = %v", c.contentType, err)
+			continue
+		}
+		if got, _ := json.Marshal(req.GetAll()); string(got) != want {
+			t.Errorf("%s: GetAll() = %s, want %s", c.contentType, got, want)
+		}
+		if age, ok := req.GetAll()["age"].(json.Number); !ok || age != "30" {
+			t.Errorf("%s: age = %#v, want json.Number", c.contentType, req.GetAll()["age"])
+		}
+	}
+}
+
+func TestDecodeTOMLTime(t *testing.T) {
+	req, err := parseBody(t, "application/toml", []byte("created = 1979-05-27T07:32:00Z\n[owner]\nname = \"Tom\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"created":"1979-05-27T07:32:00Z","owner":{"name":"Tom"}}`
+	if got, _ := json.Marshal(req.GetAll()); string(got) != want {
+		t.Errorf("GetAll() = %s, want %s", got, want)
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<user id="7" xmlns="urn:example">
+	<name>Jo</name>
+	<tag>a</tag>
+	<tag>b</tag>
+	<note lang="en">Hello</note>
+	<address><city>Jakarta</city></address>
+	<empty/>
+</user>`
+	req, err := parseBody(t, "application/xml", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"@id":"7","address":{"city":"Jakarta"},"empty":"","name":"Jo","note":{"#text":"Hello","@lang":"en"},"tag":["a","b"]}`
+	if got, _ := json.Marshal(req.GetAll()); string(got) != want {
+		t.Errorf("GetAll() = %s, want %s", got, want)
+	}
+	if lang := req.GetString("note.@lang"); lang != "en" {
+		t.Errorf("GetString(note.@lang) = %q, want en", lang)
+	}
+
+	if req, err = parseBody(t, "text/xml", []byte(`<count>3</count>`)); err != nil || req.GetString("count") != "3" {
+		t.Errorf("text root = %v, %v", req.GetAll(), err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	for contentType, body := range map[string]string{
+		"application/yaml": "name: [unclosed",
+		"application/toml": "name = ",
+		"application/xml":  "<user><name>Jo</user>",
+		"text/yaml":        "- a\n- b\n",
+	} {
+		if _, err := parseBody(t, contentType, []byte(body)); !errors.Is(err, ErrMalformedBody) {
+			t.Errorf("%s: ParseRequest() = %v, want ErrMalformedBody", contentType, err)
+		}
+	}
+	if _, err := parseBody(t, "application/json", []byte(`{"name":`)); !errors.Is(err, ErrMalformedJSON) {
+		t.Errorf("json: ParseRequest() = %v, want ErrMalformedJSON", err)
+	}
+	if _, err := parseBody(t, "application/x-custom", []byte(`x`)); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("custom: ParseRequest() = %v, want ErrUnsupportedMediaType", err)
+	}
+}
+
+func TestFloatNumbers(t *testing.T) {
+	r := httptest.NewRequest("POST", `/?filter={"min":5}`, bytes.NewReader([]byte(`{"price":19.5,"ids":[1,2],"user":{"age":30}}`)))
+	r.Header.Set("Content-Type", "application/json")
+	req, err := ParseRequest(r, WithFloatNumbers(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := req.GetAll()
+	if price, ok := params["price"].(float64); !ok || price != 19.5 {
+		t.Errorf("price = %#v, want float64", params["price"])
+	}
+	if age, ok := params["user"].(map[string]interface{})["age"].(float64); !ok || age != 30 {
+		t.Errorf("user.age = %#v, want float64", params["user"])
+	}
+	if id, ok := params["ids"].([]interface{})[1].(float64); !ok || id != 2 {
+		t.Errorf("ids = %#v, want float64 items", params["ids"])
+	}
+	if min, ok := params["filter"].(map[string]interface{})["min"].(float64); !ok || min != 5 {
+		t.Errorf("filter.min = %#v, want float64", params["filter"])
+	}
+	if v := req.GetInt64("user.age"); v != 30 {
+		t.Errorf("GetInt64(user.age) = %d, want 30", v)
+	}
+
+	defer func(enabled bool) { FloatNumbers = enabled }(FloatNumbers)
+	FloatNumbers = true
+	req, _ = parseBody(t, "application/json", []byte(`{"n":1}`))
+	if _, ok := req.GetAll()["n"].(float64); !ok {
+		t.Errorf("FloatNumbers n = %#v, want float64", req.GetAll()["n"])
+	}
+	r = httptest.NewRequest("POST", "/", bytes.NewReader([]byte(`{"n":1}`)))
+	r.Header.Set("Content-Type", "application/json")
+	if req, _ = ParseRequest(r, WithFloatNumbers(false)); req.GetAll()["n"] != json.Number("1") {
+		t.Errorf("WithFloatNumbers(false) n = %#v, want json.Number", req.GetAll()["n"])
+	}
+}
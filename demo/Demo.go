@@ -26,10 +26,9 @@ func main() {
 func index(w http.ResponseWriter, r *http.Request) {
 	var req = jumper.TouchRequest(r, w) // Plug Request without clearing io.Reader
 	req = jumper.PlugRequest(r, w)      // Plug Request normally
-	var res = jumper.PlugResponse(w)
+	var res = jumper.PlugResponseFor(w, r) // Negotiate reply format from Accept header
 
-	/*vn := req.GetMap("list")["obj"]
-	fmt.Println(vn.(map[string]interface{})["id"].([]interface{})[0])*/
+	fmt.Println(req.GetInt("list.obj.id[0]")) // ?list={"obj":{"id":[1,2,3]}}
 
 	if req.Filled("test") {
 		fmt.Println(req.GetString("test"))
@@ -0,0 +1,305 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder write v into w, v is encoded using its `json` tags regardless of format.
+type Encoder func(w io.Writer, v any) error
+
+type encoderEntry struct {
+	mediaType string
+	encode    Encoder
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = []encoderEntry{
+		{"application/json", encodeJSON},
+		{"application/xml", encodeXML},
+		{"text/xml", encodeXML},
+		{"application/yaml", encodeYAML},
+		{"application/x-yaml", encodeYAML},
+		{"text/yaml", encodeYAML},
+		{"application/msgpack", encodeMsgpack},
+		{"application/x-msgpack", encodeMsgpack},
+		{"application/vnd.msgpack", encodeMsgpack},
+		{"application/cbor", encodeCBOR},
+	}
+)
+
+// RegisterEncoder register response encoder for media type, replacing existing one.
+func RegisterEncoder(mediaType string, fn Encoder) {
+	mediaType = strings.ToLower(mediaType)
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	for i, e := range encoders {
+		if e.mediaType == mediaType {
+			encoders[i].encode = fn
+			return
+		}
+	}
+	encoders = append(encoders, encoderEntry{mediaType, fn})
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiate pick encoder for Accept header, empty accept means JSON.
+// Range with q=0 exclude media types it match unless more specific range allow them.
+// Returned encoder is nil when nothing acceptable is registered.
+func negotiate(accept string) (string, Encoder) {
+	if strings.TrimSpace(accept) == "" {
+		return "application/json", encodeJSON
+	}
+	ranges := acceptRanges(accept)
+
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	for _, ar := range ranges {
+		if ar.q <= 0 {
+			break
+		}
+		for _, e := range encoders {
+			if matchMediaRange(ar.mediaType, e.mediaType) && quality(ranges, e.mediaType) > 0 {
+				return e.mediaType, e.encode
+			}
+		}
+	}
+	return "", nil
+}
+
+// acceptRanges parse Accept header into ranges ordered by q, then by specificity.
+func acceptRanges(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		ar := acceptRange{mediaType: strings.ToLower(strings.TrimSpace(fields[0])), q: 1}
+		for _, param := range fields[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					ar.q = q
+				}
+			}
+		}
+		if ar.mediaType != "" {
+			ranges = append(ranges, ar)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return strings.Count(ranges[i].mediaType, "*") < strings.Count(ranges[j].mediaType, "*")
+	})
+	return ranges
+}
+
+// quality return q of the most specific range matching mediaType.
+func quality(ranges []acceptRange, mediaType string) float64 {
+	q, specificity := 0.0, -1
+	for _, ar := range ranges {
+		if !matchMediaRange(ar.mediaType, mediaType) {
+			continue
+		}
+		if s := 2 - strings.Count(ar.mediaType, "*"); s > specificity || (s == specificity && ar.q < q) {
+			q, specificity = ar.q, s
+		}
+	}
+	return q
+}
+
+func matchMediaRange(mediaRange, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+func encodeYAML(w io.Writer, v any) error {
+	g, err := generic(v)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	if err = enc.Encode(g); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func encodeMsgpack(w io.Writer, v any) error {
+	g, err := generic(v)
+	if err != nil {
+		return err
+	}
+	return msgpack.NewEncoder(w).Encode(g)
+}
+
+func encodeCBOR(w io.Writer, v any) error {
+	g, err := generic(v)
+	if err != nil {
+		return err
+	}
+	return cbor.NewEncoder(w).Encode(g)
+}
+
+// generic round trip v through JSON so every format honor `json` tags and custom marshalers.
+func generic(v any) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var g interface{}
+	if err = dec.Decode(&g); err != nil {
+		return nil, err
+	}
+	return plainNumbers(g), nil
+}
+
+func plainNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(t.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = plainNumbers(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = plainNumbers(val)
+		}
+	}
+	return v
+}
+
+// encodeXML write v under <response> root, the inverse of XML decoder: keys prefixed by '@' become attributes,
+// '#text' become text and array become repeated elements.
+func encodeXML(w io.Writer, v any) error {
+	g, err := generic(v)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if list, ok := g.([]interface{}); ok {
+		g = map[string]interface{}{"item": list}
+	}
+	enc := xml.NewEncoder(w)
+	if err = encodeXMLElement(enc, "response", g); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+var xmlInvalidName = regexp.MustCompile(`[^A-Za-z0-9_.\-]`)
+
+func xmlName(key string) string {
+	name := xmlInvalidName.ReplaceAllString(key, "_")
+	if name == "" || !(name[0] == '_' || (name[0] >= 'A' && name[0] <= 'Z') || (name[0] >= 'a' && name[0] <= 'z')) {
+		name = "_" + name
+	}
+	return name
+}
+
+func encodeXMLElement(enc *xml.Encoder, name string, v interface{}) error {
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if inner, nested := item.([]interface{}); nested {
+				item = map[string]interface{}{"item": inner}
+			}
+			if err := encodeXMLElement(enc, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	m, isMap := v.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.HasPrefix(k, "@") {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: xmlName(k[1:])}, Value: xmlText(m[k])})
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	if isMap {
+		if text, ok := m["#text"]; ok {
+			if err := enc.EncodeToken(xml.CharData(xmlText(text))); err != nil {
+				return err
+			}
+		}
+		for _, k := range keys {
+			if strings.HasPrefix(k, "@") || k == "#text" {
+				continue
+			}
+			if err := encodeXMLElement(enc, k, m[k]); err != nil {
+				return err
+			}
+		}
+	} else if v != nil {
+		if err := enc.EncodeToken(xml.CharData(xmlText(v))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func xmlText(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,19 @@
+package jumper
+
+import "testing"
+
+func TestNegotiateExclusion(t *testing.T) {
+	for accept, want := range map[string]string{
+		"":                       "application/json",
+		"application/*;q=0, */*": "text/xml",
+		"application/*;q=0, application/yaml, */*;q=0.5": "application/yaml",
+		"*/*;q=0":                               "",
+		"application/json;q=0":                  "",
+		"text/*;q=0.5, text/xml;q=0, */*;q=0.1": "text/yaml",
+		"application/json;q=0, */*":             "application/xml",
+	} {
+		if got, _ := negotiate(accept); got != want {
+			t.Errorf("negotiate(%q) = %q, want %q", accept, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package jumper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Kinds of RequestError returned by ParseRequest, match them with errors.Is.
+var (
+	ErrMalformedJSON        = errors.New("malformed json body")
+	ErrMalformedBody        = errors.New("malformed request body")
+	ErrMalformedForm        = errors.New("malformed form body")
+	ErrMultipart            = errors.New("invalid multipart form")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrBodyTooLarge         = errors.New("request body too large")
+)
+
+// RequestError is failure of parsing request, Kind is one of the Err* above and Err is the underlying cause.
+type RequestError struct {
+	Kind error
+	Err  error
+}
+
+func newRequestError(kind error, err error) *RequestError {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		kind = ErrBodyTooLarge
+	}
+	return &RequestError{Kind: kind, Err: err}
+}
+
+func (e *RequestError) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// HttpStatusCode suggest HTTP status to reply the failure with.
+func (e *RequestError) HttpStatusCode() int {
+	switch e.Kind {
+	case ErrBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+// Kinds of ParamError returned by E getters, match them with errors.Is.
+var (
+	ErrParamMissing  = errors.New("param is missing")
+	ErrParamType     = errors.New("param has wrong type")
+	ErrParamOverflow = errors.New("param is out of range")
+	ErrParamSyntax   = errors.New("param is not parsable")
+)
+
+// ParamError is failure of getting typed param, Err is one of the ErrParam* above.
+type ParamError struct {
+	Key   string
+	Value interface{}
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// ParamErrors collect ParamError of every bad element returned by slice getters.
+type ParamErrors []*ParamError
+
+func (e ParamErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ParamErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Error is failure replied by ReplyError as failed envelope, Err is the cause and never replied.
+type Error struct {
+	HttpStatusCode int
+	Number         string
+	Code           string
+	Message        string
+	Data           any
+	Vars           Vars
+	Err            error
+}
+
+// NewError create Error, 'data' arguments only used on index 0 */
+//
+//	var ErrUserNotFound = jumper.NewError(404, "4040001", "USER_NOT_FOUND", "User {id} not found")
+//	return ErrUserNotFound.With(jumper.Vars{"id": id}).Wrap(err)
+func NewError(httpStatusCode int, number string, code string, message string, data ...any) *Error {
+	e := &Error{HttpStatusCode: httpStatusCode, Number: number, Code: code, Message: message}
+	if len(data) > 0 {
+		e.Data = data[0]
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := e.Code + ": " + fillPlaceholders(e.Message, e.Vars)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is match any Error of the same code, so copies made by Wrap, With and WithData still match errors.Is.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap return copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// With return copy of e whose message placeholders are filled from vars.
+func (e *Error) With(vars Vars) *Error {
+	c := *e
+	c.Vars = make(Vars, len(e.Vars)+len(vars))
+	for k, v := range e.Vars {
+		c.Vars[k] = v
+	}
+	for k, v := range vars {
+		c.Vars[k] = v
+	}
+	return &c
+}
+
+// WithData return copy of e replied with data.
+func (e *Error) WithData(data any) *Error {
+	c := *e
+	c.Data = data
+	return &c
+}
+
+// Errors replied by ReplyError for known failures, reassign them to change the envelope.
+var (
+	ErrBadRequest = NewError(http.StatusBadRequest, "4000000", "BAD_REQUEST", "Bad request")
+	ErrNotFound   = NewError(http.StatusNotFound, "4040000", "NOT_FOUND", "Not found")
+	ErrTimeout    = NewError(http.StatusGatewayTimeout, "5040000", "TIMEOUT", "Request timeout")
+	ErrInternal   = NewError(http.StatusInternalServerError, "5000000", "INTERNAL_ERROR", "Internal server error")
+)
+
+// ErrorMapper convert foreign error into Error, nil means err is not handled.
+type ErrorMapper func(err error) *Error
+
+var (
+	errorMappersMu sync.RWMutex
+	errorMappers   = []ErrorMapper{
+		func(err error) *Error {
+			var reqErr *RequestError
+			if errors.As(err, &reqErr) {
+				e := ErrBadRequest.Wrap(err)
+				e.HttpStatusCode = reqErr.HttpStatusCode()
+				return e
+			}
+			var paramErr *ParamError
+			if errors.As(err, &paramErr) {
+				return ErrBadRequest.Wrap(err)
+			}
+			return nil
+		},
+		func(err error) *Error {
+			if errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrObjectNotFound) {
+				return ErrNotFound.Wrap(err)
+			}
+			return nil
+		},
+		func(err error) *Error {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return ErrTimeout.Wrap(err)
+			}
+			return nil
+		},
+	}
+)
+
+// RegisterErrorMapper register mapper used by ReplyError, mapper registered later is tried first.
+func RegisterErrorMapper(fn ErrorMapper) {
+	errorMappersMu.Lock()
+	defer errorMappersMu.Unlock()
+	errorMappers = append(errorMappers, fn)
+}
+
+// MapError reply e for any error matching target with errors.Is.
+//
+//	jumper.MapError(redis.Nil, ErrCacheMiss)
+func MapError(target error, e *Error) {
+	RegisterErrorMapper(func(err error) *Error {
+		if errors.Is(err, target) {
+			return e.Wrap(err)
+		}
+		return nil
+	})
+}
+
+// UnhandledError is called by ReplyError with error replied as ErrInternal, e.g. to log it.
+var UnhandledError func(err error)
+
+// toError find Error of err by errors.As, then by registered mappers, ok is false when nothing match.
+func toError(err error) (e *Error, ok bool) {
+	if errors.As(err, &e) {
+		return e, true
+	}
+	errorMappersMu.RLock()
+	defer errorMappersMu.RUnlock()
+	for i := len(errorMappers) - 1; i >= 0; i-- {
+		if e = errorMappers[i](err); e != nil {
+			return e, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,122 @@
+package jumper
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type errorReply struct {
+	Status        int    `json:"status"`
+	StatusNumber  string `json:"status_number"`
+	StatusCode    string `json:"status_code"`
+	StatusMessage string `json:"status_message"`
+}
+
+func replyError(t *testing.T, err error) (int, errorReply) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	if e := PlugResponseFor(w, nil).ReplyError(err); e != nil {
+		t.Fatal(e)
+	}
+	var body errorReply
+	if e := json.Unmarshal(w.Body.Bytes(), &body); e != nil {
+		t.Fatal(e)
+	}
+	return w.Code, body
+}
+
+func TestErrorCopiesMatch(t *testing.T) {
+	errUserNotFound := NewError(404, "4040001", "USER_NOT_FOUND", "User {id} not found")
+	cause := sql.ErrNoRows
+	err := fmt.Errorf("service: %w", errUserNotFound.With(Vars{"id": 7}).Wrap(cause).WithData("x"))
+	if !errors.Is(err, errUserNotFound) || !errors.Is(err, cause) {
+		t.Errorf("errors.Is(copy) = %t, %t, want true", errors.Is(err, errUserNotFound), errors.Is(err, cause))
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is(copy, ErrNotFound) = true, want false for different code")
+	}
+	if errUserNotFound.Vars != nil || errUserNotFound.Err != nil || errUserNotFound.Data != nil {
+		t.Errorf("With and Wrap modified original: %+v", errUserNotFound)
+	}
+
+	code, body := replyError(t, err)
+	if code != 404 || body.StatusCode != "USER_NOT_FOUND" || body.StatusMessage != "User 7 not found" {
+		t.Errorf("ReplyError() = %d %+v", code, body)
+	}
+}
+
+func TestReplyErrorRequestError(t *testing.T) {
+	for kind, status := range map[error]int{
+		ErrBodyTooLarge:         413,
+		ErrUnsupportedMediaType: 415,
+		ErrMalformedJSON:        400,
+		ErrMultipart:            400,
+	} {
+		code, body := replyError(t, &RequestError{Kind: kind, Err: errors.New("decoder internals")})
+		if code != status || body.StatusCode != ErrBadRequest.Code || strings.Contains(body.StatusMessage, "internals") {
+			t.Errorf("ReplyError(%v) = %d %+v, want %d", kind, code, body, status)
+		}
+	}
+	if code, _ := replyError(t, &ParamError{Key: "page", Err: ErrParamSyntax}); code != 400 {
+		t.Errorf("ReplyError(ParamError) = %d, want 400", code)
+	}
+	if code, _ := replyError(t, fmt.Errorf("query: %w", context.DeadlineExceeded)); code != 504 {
+		t.Errorf("ReplyError(DeadlineExceeded) = %d, want 504", code)
+	}
+}
+
+func TestErrorMapperOrder(t *testing.T) {
+	errorMappersMu.Lock()
+	saved := append([]ErrorMapper(nil), errorMappers...)
+	errorMappersMu.Unlock()
+	defer func() {
+		errorMappersMu.Lock()
+		errorMappers = saved
+		errorMappersMu.Unlock()
+	}()
+
+	errDuplicate := errors.New("duplicate key")
+	errConflict := NewError(409, "4090001", "CONFLICT", "Already exists")
+	errTaken := NewError(409, "4090002", "EMAIL_TAKEN", "Email is already registered")
+	MapError(errDuplicate, errConflict)
+	MapError(errDuplicate, errTaken)
+	if _, body := replyError(t, fmt.Errorf("insert: %w", errDuplicate)); body.StatusCode != "EMAIL_TAKEN" {
+		t.Errorf("ReplyError() code = %s, want EMAIL_TAKEN from mapper registered last", body.StatusCode)
+	}
+
+	RegisterErrorMapper(func(err error) *Error {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errConflict.Wrap(err)
+		}
+		return nil
+	})
+	if _, body := replyError(t, sql.ErrNoRows); body.StatusCode != "CONFLICT" {
+		t.Errorf("ReplyError(sql.ErrNoRows) code = %s, want CONFLICT overriding built-in mapper", body.StatusCode)
+	}
+}
+
+func TestUnhandledError(t *testing.T) {
+	defer func(fn func(error)) { UnhandledError = fn }(UnhandledError)
+	var handled []error
+	UnhandledError = func(err error) { handled = append(handled, err) }
+
+	secret := errors.New("pq: password authentication failed for user admin")
+	code, body := replyError(t, secret)
+	if code != 500 || body.StatusCode != ErrInternal.Code || strings.Contains(body.StatusMessage, "password") {
+		t.Errorf("ReplyError(unknown) = %d %+v, want 500 INTERNAL_ERROR without cause", code, body)
+	}
+	if len(handled) != 1 || handled[0] != secret {
+		t.Errorf("UnhandledError got %v, want the unknown error once", handled)
+	}
+
+	replyError(t, ErrNotFound.Wrap(secret))
+	if len(handled) != 1 {
+		t.Errorf("UnhandledError called for mapped error: %v", handled)
+	}
+}
@@ -2,16 +2,18 @@ package jumper
 
 import (
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
 )
 
 type File struct {
-	f multipart.File
-	fh *multipart.FileHeader
-	name string
+	f           multipart.File
+	fh          *multipart.FileHeader
+	name        string
+	contentType string
 }
 
 func (f *File) GetFile() multipart.File {
@@ -27,38 +29,55 @@ func (f *File) Name() string {
 }
 
 func (f *File) Store(path string, pattern string, perm os.FileMode) (string, error) {
+	name, err := storeTemp(f.GetFile(), path, pattern, perm)
+	if err != nil {
+		return "", err
+	}
+	f.name = name
+	return filepath.Base(f.name), nil
+}
+
+func (f *File) StoreAs(path string, name string, perm os.FileMode) error {
+	if err := storeAs(f.GetFile(), path, name, perm); err != nil {
+		return err
+	}
+	f.name = name
+	return nil
+}
+
+// storeTemp copy r into new file created from pattern in path, return full name of the file.
+func storeTemp(r io.Reader, path string, pattern string, perm os.FileMode) (string, error) {
 	os.MkdirAll(path, perm)
 
-	file, err := ioutil.TempFile(path, pattern)
+	file, err := os.CreateTemp(path, pattern)
 	if err != nil {
 		return "", errors.New("failed to store file")
 	}
 	defer file.Close()
 
-	fBytes, err := ioutil.ReadAll(f.GetFile())
-	if err != nil {
-		return "", errors.New("failed to read file")
+	if _, err = io.Copy(file, r); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to read file: %w", err)
 	}
-	file.Write(fBytes)
-
 	os.Chmod(file.Name(), perm)
-	f.name = file.Name()
-	//here we save our file to our path
-	return filepath.Base(f.name), nil
+	return file.Name(), nil
 }
 
-func (f *File) StoreAs(path string, name string, perm os.FileMode) error {
+// storeAs copy r into path/name, partially written file is removed on failure.
+func storeAs(r io.Reader, path string, name string, perm os.FileMode) error {
 	os.MkdirAll(path, perm)
 
-	fBytes, err := ioutil.ReadAll(f.GetFile())
+	file, err := os.OpenFile(filepath.Join(path, name), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
 	if err != nil {
-		return errors.New("failed to read file")
+		return errors.New("failed to store file")
 	}
-
-	err = ioutil.WriteFile(path+"/"+name, fBytes, perm)
-	if err != nil {
+	if _, err = io.Copy(file, r); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if err = file.Close(); err != nil {
 		return errors.New("failed to store file")
 	}
-	f.name = name
 	return nil
-}
\ No newline at end of file
+}
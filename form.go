@@ -0,0 +1,269 @@
+package jumper
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// setParams store query or form values. Bracket keys are expanded into nested params like JSON body:
+// "user[name]" become object, "user[roles][]" become array and "items[0][qty]" become array of object.
+//
+// Keys are applied in sorted order, so key always come before its longer bracket forms and result never
+// depend on map order. When name hold both value and nested keys ("user" and "user[name]"), nested value win,
+// the plain value is still readable by GetRaw. Appended rows ("items[][qty]") are placed after indexed ones
+// ("items[0][qty]"), see indexLists. Bracket keys of query and form share one tree, so form values are merged
+// into query values of the same name instead of replacing them.
+func (r *Request) setParams(values map[string][]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nested := map[string]bool{}
+	for _, key := range keys {
+		segments, ok := bracketSegments(key)
+		if !ok {
+			if _, ok = r.fields[key]; !ok {
+				r.params[key] = scan(values[key], r.sniffs(key))
+			}
+			continue
+		}
+		r.expandKey(key, segments, values[key])
+		nested[segments[0]] = true
+	}
+	for name := range nested {
+		r.params[name] = indexLists(r.fields[name])
+	}
+}
+
+// expandKey expand values of bracket key into tree of its name kept in fields. Appended rows continue after
+// rows already expanded for the same key, so repeated key in query and form or in streamed parts add rows.
+func (r *Request) expandKey(key string, segments []string, values []string) {
+	if r.fields == nil {
+		r.fields = map[string]interface{}{}
+		r.rows = map[string]int{}
+	}
+	name := segments[0]
+	sniff := r.sniffs(key) || r.sniffs(name)
+	r.fields[name] = expand(r.fields[name], segments[1:], values, r.rows[key], sniff)
+	r.rows[key] += len(values)
+}
+
+// sniffs report whether query or form value of key is decoded as JSON, see WithJSONSniffing and WithJSONFields.
+func (r *Request) sniffs(key string) bool {
+	if r.jsonFields != nil {
+		return r.jsonFields[key]
+	}
+	return r.sniffJSON
+}
+
+// GetRaw get original string of query or form key as received, without JSON sniffing and bracket expansion.
+// Form value is preferred over query value like params, missing key return empty string.
+func (r *Request) GetRaw(key string) string {
+	if values, ok := r.form[key]; ok && len(values) > 0 {
+		return values[0]
+	}
+	return r.query.Get(key)
+}
+
+// bracketSegments split "user[roles][]" into ["user", "roles", ""], ok is false for key without brackets.
+func bracketSegments(key string) ([]string, bool) {
+	i := strings.IndexByte(key, '[')
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return nil, false
+	}
+	segments := []string{key[:i]}
+	for rest := key[i:]; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end == -1 {
+			return nil, false
+		}
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return segments, true
+}
+
+// expand place values under segments of node, node holding value is replaced by object.
+// Empty segment append rows kept under "" key until indexLists number them. Values of "items[][name]"
+// are spread by position starting at row at, so each row of repeated fields become one object.
+func expand(node interface{}, segments []string, values []string, at int, sniff bool) interface{} {
+	if len(segments) == 0 {
+		return scan(values, sniff)
+	}
+	segment, rest := segments[0], segments[1:]
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	if segment != "" {
+		m[segment] = expand(m[segment], rest, values, at, sniff)
+		return m
+	}
+
+	rows, _ := m[""].([]interface{})
+	for i, value := range values {
+		if len(rest) == 0 {
+			rows = append(rows, identify(value, sniff))
+		} else if at+i < len(rows) {
+			rows[at+i] = expand(rows[at+i], rest, []string{value}, 0, sniff)
+		} else {
+			rows = append(rows, expand(nil, rest, []string{value}, 0, sniff))
+		}
+	}
+	m[""] = rows
+	return m
+}
+
+// indexLists return copy of node with appended rows numbered after the highest index, and object keyed only
+// by index, like "items[0]" and "items[1]", turned into array ordered by index. Object having any other key
+// stay object. Node is not modified, so expanded tree can keep growing.
+func indexLists(node interface{}) interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = indexLists(item)
+		}
+		return list
+	case map[string]interface{}:
+		rows, _ := v[""].([]interface{})
+		m := make(map[string]interface{}, len(v)+len(rows))
+		indexes := make([]int, 0, len(v)+len(rows))
+		next := 0
+		for k, item := range v {
+			if k == "" {
+				continue
+			}
+			m[k] = indexLists(item)
+			if i, ok := listIndex(k); ok {
+				indexes = append(indexes, i)
+				if i >= next {
+					next = i + 1
+				}
+			}
+		}
+		for n, row := range rows {
+			m[strconv.Itoa(next+n)] = indexLists(row)
+			indexes = append(indexes, next+n)
+		}
+		if len(indexes) == 0 || len(indexes) != len(m) {
+			return m
+		}
+		sort.Ints(indexes)
+		list := make([]interface{}, len(indexes))
+		for n, i := range indexes {
+			list[n] = m[strconv.Itoa(i)]
+		}
+		return list
+	}
+	return node
+}
+
+// listIndex parse canonical non-negative index key, "01" is not an index.
+func listIndex(key string) (int, bool) {
+	i, err := strconv.Atoi(key)
+	return i, err == nil && i >= 0 && strconv.Itoa(i) == key
+}
+
+// paramsOf return params to decode into t, values from query and form are textual,
+// so numeric and boolean strings are converted when t expect number or bool.
+func (r *Request) paramsOf(t reflect.Type) Params {
+	textual := map[string]bool{}
+	for _, values := range []map[string][]string{r.query, r.form} {
+		for key := range values {
+			if segments, ok := bracketSegments(key); ok {
+				key = segments[0]
+			}
+			textual[key] = true
+		}
+	}
+	if len(textual) == 0 {
+		return r.params
+	}
+
+	params := make(Params, len(r.params))
+	for key, value := range r.params {
+		params[key] = value
+		if !textual[key] {
+			continue
+		}
+		if ft, ok := fieldType(t, key); ok {
+			params[key] = coerce(value, ft)
+		}
+	}
+	return params
+}
+
+// fieldType find type of struct field decoded from key, matching names like encoding/json.
+func fieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem(), true
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, ok := fieldName(sf)
+			if !ok || !sf.IsExported() && !sf.Anonymous {
+				continue
+			}
+			if name == "" {
+				if ft, ok := fieldType(sf.Type, key); ok {
+					return ft, true
+				}
+			} else if strings.EqualFold(name, key) {
+				return sf.Type, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// coerce copy v converting string into json.Number or bool where t expect them.
+func coerce(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch value := v.(type) {
+	case string:
+		s := strings.TrimSpace(value)
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			if isNumberLiteral(s) {
+				return json.Number(s)
+			}
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = coerce(item, t.Elem())
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[k] = item
+			if ft, ok := fieldType(t, k); ok {
+				m[k] = coerce(item, ft)
+			}
+		}
+		return m
+	}
+	return v
+}
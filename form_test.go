@@ -0,0 +1,88 @@
+package jumper
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func parseQuery(t *testing.T, query string) *Request {
+	t.Helper()
+	req, err := ParseRequest(httptest.NewRequest("GET", "/?"+query, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestBracketExpansionDeterministic(t *testing.T) {
+	const query = "user=abc&user[name]=x&items[0][q]=1&items[][q]=2&items[][q]=3&tags=a&tags[]=b"
+	want := `{"items":[{"q":"1"},{"q":"2"},{"q":"3"}],"tags":["b"],"user":{"name":"x"}}`
+	for i := 0; i < 200; i++ {
+		req := parseQuery(t, query)
+		got, _ := json.Marshal(req.GetAll())
+		if string(got) != want {
+			t.Fatalf("run %d: GetAll() = %s, want %s", i, got, want)
+		}
+		if raw := req.GetRaw("user"); raw != "abc" {
+			t.Fatalf("GetRaw(user) = %q, want abc", raw)
+		}
+	}
+}
+
+func TestBracketExpansion(t *testing.T) {
+	for query, want := range map[string]string{
+		"user[name]=Jo&user[roles][]=admin&user[roles][]=dev": `{"user":{"name":"Jo","roles":["admin","dev"]}}`,
+		"items[1][qty]=5&items[0][qty]=2":                     `{"items":[{"qty":"2"},{"qty":"5"}]}`,
+		"items[][name]=a&items[][name]=b&items[][qty]=1":      `{"items":[{"name":"a","qty":"1"},{"name":"b"}]}`,
+		"m[a]=1&m[0]=2&m[]=3":                                 `{"m":{"0":"2","1":"3","a":"1"}}`,
+		"a[b]=1&a[b][c]=2":                                    `{"a":{"b":{"c":"2"}}}`,
+	} {
+		got, _ := json.Marshal(parseQuery(t, query).GetAll())
+		if string(got) != want {
+			t.Errorf("%s: GetAll() = %s, want %s", query, got, want)
+		}
+	}
+}
+
+func TestParseToForm(t *testing.T) {
+	type order struct {
+		Items []struct {
+			Qty   int     `json:"qty"`
+			Price float64 `json:"price"`
+		} `json:"items"`
+		Paid bool `json:"paid"`
+	}
+	r := httptest.NewRequest("POST", "/", strings.NewReader("items[0][qty]=2&items[0][price]=1.5&paid=true"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, err := ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := ParseTo[order](req)
+	if err != nil || len(o.Items) != 1 || o.Items[0].Qty != 2 || o.Items[0].Price != 1.5 || !o.Paid {
+		t.Errorf("ParseTo() = %+v, %v", o, err)
+	}
+}
+
+func TestBracketExpansionQueryAndForm(t *testing.T) {
+	for _, c := range []struct {
+		query, form, want string
+	}{
+		{"items[0][a]=1", "items[1][a]=2", `{"items":[{"a":"1"},{"a":"2"}]}`},
+		{"items[][a]=1", "items[][a]=2", `{"items":[{"a":"1"},{"a":"2"}]}`},
+		{"user[name]=x", "user=abc&user[age]=3", `{"user":{"age":"3","name":"x"}}`},
+		{"page=1", "page=2", `{"page":"2"}`},
+	} {
+		r := httptest.NewRequest("POST", "/?"+c.query, strings.NewReader(c.form))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req, err := ParseRequest(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := json.Marshal(req.GetAll()); string(got) != c.want {
+			t.Errorf("%s + %s: GetAll() = %s, want %s", c.query, c.form, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package jumper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetStrict toggle strict conversion on E getters and their Ptr and plain counterparts.
+// Strict request refuse lossy coercions: fractional number into integer, bool into number,
+// number into bool and object or array into string.
+func (r *Request) SetStrict(strict bool) *Request {
+	r.strict = strict
+	return r
+}
+
+// IsStrict report whether strict conversion is enabled, see SetStrict.
+func (r *Request) IsStrict() bool {
+	return r.strict
+}
+
+func (r *Request) paramError(key string, value interface{}, err error) error {
+	return &ParamError{Key: key, Value: value, Err: err}
+}
+
+// present lookup key and treat null as missing.
+func (r *Request) present(key string) (interface{}, error) {
+	param, ok := r.lookup(key)
+	if !ok || param == nil {
+		return nil, r.paramError(key, nil, ErrParamMissing)
+	}
+	return param, nil
+}
+
+func numError(err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return ErrParamOverflow
+	}
+	return ErrParamSyntax
+}
+
+// GetStringE get key value as string, error is *ParamError.
+func (r *Request) GetStringE(key string) (string, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return "", err
+	}
+	return r.toString(key, param)
+}
+
+func (r *Request) toString(key string, param interface{}) (string, error) {
+	switch v := param.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	if r.strict {
+		switch reflect.ValueOf(param).Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+			return "", r.paramError(key, param, ErrParamType)
+		}
+	}
+	return fmt.Sprintf("%v", param), nil
+}
+
+// GetInt64E get key value as int64, error is *ParamError.
+func (r *Request) GetInt64E(key string) (int64, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return 0, err
+	}
+	return r.toInt64(key, param)
+}
+
+func (r *Request) toInt64(key string, param interface{}) (int64, error) {
+	switch v := param.(type) {
+	case json.Number:
+		i, err := strconv.ParseInt(v.String(), 10, 64)
+		if err == nil {
+			return i, nil
+		} else if errors.Is(err, strconv.ErrRange) {
+			return 0, r.paramError(key, param, ErrParamOverflow)
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return r.floatToInt64(key, param, f)
+	case float64:
+		return r.floatToInt64(key, param, v)
+	case int:
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return i, nil
+	case bool:
+		if !r.strict {
+			if v {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, r.paramError(key, param, ErrParamType)
+}
+
+func (r *Request) floatToInt64(key string, param interface{}, v float64) (int64, error) {
+	if v < math.MinInt64 || v >= math.MaxInt64 || math.IsNaN(v) {
+		return 0, r.paramError(key, param, ErrParamOverflow)
+	}
+	if r.strict && v != math.Trunc(v) {
+		return 0, r.paramError(key, param, ErrParamType)
+	}
+	return int64(v), nil
+}
+
+// GetUint64E get key value as uint64, error is *ParamError.
+func (r *Request) GetUint64E(key string) (uint64, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return 0, err
+	}
+	return r.toUint64(key, param)
+}
+
+func (r *Request) toUint64(key string, param interface{}) (uint64, error) {
+	switch v := param.(type) {
+	case json.Number:
+		u, err := strconv.ParseUint(v.String(), 10, 64)
+		if err == nil {
+			return u, nil
+		} else if errors.Is(err, strconv.ErrRange) {
+			return 0, r.paramError(key, param, ErrParamOverflow)
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return r.floatToUint64(key, param, f)
+	case float64:
+		return r.floatToUint64(key, param, v)
+	case int:
+		if v < 0 {
+			return 0, r.paramError(key, param, ErrParamOverflow)
+		}
+		return uint64(v), nil
+	case string:
+		s := strings.TrimSpace(v)
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			if strings.HasPrefix(s, "-") {
+				if _, ierr := strconv.ParseInt(s, 10, 64); ierr == nil {
+					return 0, r.paramError(key, param, ErrParamOverflow)
+				}
+			}
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return u, nil
+	case bool:
+		if !r.strict {
+			if v {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, r.paramError(key, param, ErrParamType)
+}
+
+func (r *Request) floatToUint64(key string, param interface{}, v float64) (uint64, error) {
+	if v < 0 || v >= math.MaxUint64 || math.IsNaN(v) {
+		return 0, r.paramError(key, param, ErrParamOverflow)
+	}
+	if r.strict && v != math.Trunc(v) {
+		return 0, r.paramError(key, param, ErrParamType)
+	}
+	return uint64(v), nil
+}
+
+// GetInt32E get key value as int32, error is *ParamError.
+func (r *Request) GetInt32E(key string) (int32, error) {
+	i, err := r.GetInt64E(key)
+	if err != nil {
+		return 0, err
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, r.paramError(key, i, ErrParamOverflow)
+	}
+	return int32(i), nil
+}
+
+// GetIntE get key value as int, error is *ParamError.
+func (r *Request) GetIntE(key string) (int, error) {
+	i, err := r.GetInt64E(key)
+	if err != nil {
+		return 0, err
+	}
+	if i < math.MinInt || i > math.MaxInt {
+		return 0, r.paramError(key, i, ErrParamOverflow)
+	}
+	return int(i), nil
+}
+
+// GetUint32E get key value as uint32, error is *ParamError.
+func (r *Request) GetUint32E(key string) (uint32, error) {
+	u, err := r.GetUint64E(key)
+	if err != nil {
+		return 0, err
+	}
+	if u > math.MaxUint32 {
+		return 0, r.paramError(key, u, ErrParamOverflow)
+	}
+	return uint32(u), nil
+}
+
+// GetUintE get key value as uint, error is *ParamError.
+func (r *Request) GetUintE(key string) (uint, error) {
+	u, err := r.GetUint64E(key)
+	if err != nil {
+		return 0, err
+	}
+	if u > math.MaxUint {
+		return 0, r.paramError(key, u, ErrParamOverflow)
+	}
+	return uint(u), nil
+}
+
+// GetFloat64E get key value as float64, error is *ParamError.
+func (r *Request) GetFloat64E(key string) (float64, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return 0, err
+	}
+	return r.toFloat64(key, param)
+}
+
+func (r *Request) toFloat64(key string, param interface{}) (float64, error) {
+	switch v := param.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return f, nil
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, r.paramError(key, param, numError(err))
+		}
+		return f, nil
+	case bool:
+		if !r.strict {
+			if v {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, r.paramError(key, param, ErrParamType)
+}
+
+// GetFloat32E get key value as float32, error is *ParamError.
+func (r *Request) GetFloat32E(key string) (float32, error) {
+	f, err := r.GetFloat64E(key)
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, r.paramError(key, f, ErrParamOverflow)
+	}
+	return float32(f), nil
+}
+
+// GetBoolE get key value as bool, error is *ParamError.
+// String is parsed with strconv.ParseBool, non strict request also accept positive number as true.
+func (r *Request) GetBoolE(key string) (bool, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return false, err
+	}
+	return r.toBool(key, param)
+}
+
+func (r *Request) toBool(key string, param interface{}) (bool, error) {
+	switch v := param.(type) {
+	case bool:
+		return v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+		if !r.strict {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f > 0, nil
+			}
+		}
+		return false, r.paramError(key, param, ErrParamSyntax)
+	case json.Number:
+		if !r.strict {
+			f, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return false, r.paramError(key, param, ErrParamSyntax)
+			}
+			return f > 0, nil
+		}
+	case float64:
+		if !r.strict {
+			return v > 0, nil
+		}
+	case int:
+		if !r.strict {
+			return v > 0, nil
+		}
+	}
+	return false, r.paramError(key, param, ErrParamType)
+}
@@ -0,0 +1,83 @@
+package jumper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetterOverflow(t *testing.T) {
+	req, err := parseBody(t, "application/json", []byte(`{"big":1099511627776,"huge":99999999999999999999,"neg":-1,"wide":1e40,"text":"-5","id":9007199254740993}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name string
+		get  func() error
+	}{
+		{"GetInt32E(big)", func() error { _, err := req.GetInt32E("big"); return err }},
+		{"GetUint32E(big)", func() error { _, err := req.GetUint32E("big"); return err }},
+		{"GetInt64E(huge)", func() error { _, err := req.GetInt64E("huge"); return err }},
+		{"GetUint64E(huge)", func() error { _, err := req.GetUint64E("huge"); return err }},
+		{"GetUint64E(neg)", func() error { _, err := req.GetUint64E("neg"); return err }},
+		{"GetUint64E(text)", func() error { _, err := req.GetUint64E("text"); return err }},
+		{"GetFloat32E(wide)", func() error { _, err := req.GetFloat32E("wide"); return err }},
+	} {
+		if err := c.get(); !errors.Is(err, ErrParamOverflow) {
+			t.Errorf("%s = %v, want ErrParamOverflow", c.name, err)
+		}
+	}
+	if v := req.GetInt32("big"); v != 0 {
+		t.Errorf("GetInt32(big) = %d, want 0 instead of wrapped value", v)
+	}
+	if v, err := req.GetInt64E("id"); err != nil || v != 9007199254740993 {
+		t.Errorf("GetInt64E(id) = %d, %v, want exact 9007199254740993", v, err)
+	}
+}
+
+func TestGetterStrict(t *testing.T) {
+	body := []byte(`{"frac":1.5,"whole":2.0,"flag":true,"num":"12","word":"abc","list":[1],"null":null}`)
+	loose, err := parseBody(t, "application/json", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, _ := parseBody(t, "application/json", body)
+	strict.SetStrict(true)
+
+	if v, err := loose.GetInt64E("frac"); err != nil || v != 1 {
+		t.Errorf("loose GetInt64E(frac) = %d, %v, want 1", v, err)
+	}
+	if v, err := loose.GetInt64E("flag"); err != nil || v != 1 {
+		t.Errorf("loose GetInt64E(flag) = %d, %v, want 1", v, err)
+	}
+	if v, err := strict.GetInt64E("whole"); err != nil || v != 2 {
+		t.Errorf("strict GetInt64E(whole) = %d, %v, want 2", v, err)
+	}
+	if v, err := strict.GetIntE("num"); err != nil || v != 12 {
+		t.Errorf("strict GetIntE(num) = %d, %v, want 12", v, err)
+	}
+
+	for _, c := range []struct {
+		name string
+		get  func() error
+		want error
+	}{
+		{"GetInt64E(frac)", func() error { _, err := strict.GetInt64E("frac"); return err }, ErrParamType},
+		{"GetUint64E(frac)", func() error { _, err := strict.GetUint64E("frac"); return err }, ErrParamType},
+		{"GetInt64E(flag)", func() error { _, err := strict.GetInt64E("flag"); return err }, ErrParamType},
+		{"GetFloat64E(flag)", func() error { _, err := strict.GetFloat64E("flag"); return err }, ErrParamType},
+		{"GetBoolE(num)", func() error { _, err := strict.GetBoolE("num"); return err }, ErrParamSyntax},
+		{"GetStringE(list)", func() error { _, err := strict.GetStringE("list"); return err }, ErrParamType},
+		{"GetIntE(word)", func() error { _, err := strict.GetIntE("word"); return err }, ErrParamSyntax},
+		{"GetIntE(null)", func() error { _, err := strict.GetIntE("null"); return err }, ErrParamMissing},
+		{"GetIntE(absent)", func() error { _, err := strict.GetIntE("absent"); return err }, ErrParamMissing},
+	} {
+		err := c.get()
+		var perr *ParamError
+		if !errors.Is(err, c.want) || !errors.As(err, &perr) {
+			t.Errorf("strict %s = %v, want *ParamError %v", c.name, err, c.want)
+		}
+	}
+	if v, err := loose.GetBoolE("num"); err != nil || !v {
+		t.Errorf("loose GetBoolE(num) = %t, %v, want true", v, err)
+	}
+}
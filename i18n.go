@@ -0,0 +1,400 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Message is localized status message, plain string in bundle file is Other form.
+// Plural form is chosen by "count" var using PluralRule of the locale, empty form fall back to Other.
+//
+//	ITEMS_FOUND:
+//	  zero: No item found
+//	  one: "{count} item found"
+//	  other: "{count} items found"
+type Message struct {
+	Zero  string `json:"zero,omitempty" yaml:"zero,omitempty"`
+	One   string `json:"one,omitempty" yaml:"one,omitempty"`
+	Two   string `json:"two,omitempty" yaml:"two,omitempty"`
+	Few   string `json:"few,omitempty" yaml:"few,omitempty"`
+	Many  string `json:"many,omitempty" yaml:"many,omitempty"`
+	Other string `json:"other,omitempty" yaml:"other,omitempty"`
+}
+
+type message Message
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		*m = Message{}
+		return json.Unmarshal(trimmed, &m.Other)
+	}
+	return json.Unmarshal(data, (*message)(m))
+}
+
+func (m *Message) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*m = Message{}
+		return node.Decode(&m.Other)
+	}
+	return node.Decode((*message)(m))
+}
+
+// Form return message form of plural category, "zero" form is also used for count 0 when given.
+func (m Message) Form(category string, count float64) string {
+	if count == 0 && m.Zero != "" {
+		return m.Zero
+	}
+	var form string
+	switch category {
+	case "zero":
+		form = m.Zero
+	case "one":
+		form = m.One
+	case "two":
+		form = m.Two
+	case "few":
+		form = m.Few
+	case "many":
+		form = m.Many
+	}
+	if form == "" {
+		return m.Other
+	}
+	return form
+}
+
+// PluralRule return CLDR plural category ("zero", "one", "two", "few", "many" or "other") of n.
+type PluralRule func(n float64) string
+
+var (
+	pluralRulesMu sync.RWMutex
+	pluralRules   = map[string]PluralRule{
+		"id": pluralOther,
+		"ms": pluralOther,
+		"ja": pluralOther,
+		"ko": pluralOther,
+		"zh": pluralOther,
+		"th": pluralOther,
+		"vi": pluralOther,
+		"fr": func(n float64) string {
+			if n >= 0 && n < 2 {
+				return "one"
+			}
+			return "other"
+		},
+	}
+)
+
+// DefaultPluralRule is used by language without registered rule, "one" for 1 and "other" otherwise.
+var DefaultPluralRule PluralRule = func(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralOther(float64) string {
+	return "other"
+}
+
+// RegisterPluralRule register plural rule of language like "en" or "pt-BR", replacing existing one.
+func RegisterPluralRule(lang string, rule PluralRule) {
+	pluralRulesMu.Lock()
+	defer pluralRulesMu.Unlock()
+	pluralRules[canonicalLocale(lang)] = rule
+}
+
+// pluralRule find rule of locale or its parent language.
+func pluralRule(locale string) PluralRule {
+	pluralRulesMu.RLock()
+	defer pluralRulesMu.RUnlock()
+	for _, tag := range localeParents(locale) {
+		if rule, ok := pluralRules[tag]; ok {
+			return rule
+		}
+	}
+	return DefaultPluralRule
+}
+
+// Bundle hold messages per locale keyed by status code, used by Reply family to fill status_message
+// in language negotiated from Accept-Language header.
+type Bundle struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]Message
+	fallbacks     map[string][]string
+}
+
+// DefaultBundle is bundle used by Response, it is empty so messages are replied as given until loaded.
+var DefaultBundle = NewBundle("en")
+
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		defaultLocale: canonicalLocale(defaultLocale),
+		messages:      map[string]map[string]Message{},
+		fallbacks:     map[string][]string{},
+	}
+}
+
+// DefaultLocale return locale used when nothing in Accept-Language is available.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+func (b *Bundle) empty() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.messages) == 0
+}
+
+// Add merge messages of locale into bundle.
+func (b *Bundle) Add(locale string, messages map[string]Message) {
+	locale = canonicalLocale(locale)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[locale] == nil {
+		b.messages[locale] = map[string]Message{}
+	}
+	for code, msg := range messages {
+		b.messages[locale][code] = msg
+	}
+}
+
+// SetFallback set locales tried after locale and its parent languages, before default locale.
+//
+//	bundle.SetFallback("ms", "id") // Malay user read Indonesian message when Malay one is missing
+func (b *Bundle) SetFallback(locale string, fallbacks ...string) {
+	chain := make([]string, len(fallbacks))
+	for i, fallback := range fallbacks {
+		chain[i] = canonicalLocale(fallback)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fallbacks[canonicalLocale(locale)] = chain
+}
+
+// Locales return locales having messages, in order.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Load add messages of locale read from r, format is "json" or "yaml".
+// Content is object of status code to message, see Message.
+func (b *Bundle) Load(r io.Reader, locale string, format string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	messages := map[string]Message{}
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.Unmarshal(data, &messages)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &messages)
+	default:
+		return fmt.Errorf("unsupported message bundle format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("message bundle %s: %w", locale, err)
+	}
+	b.Add(locale, messages)
+	return nil
+}
+
+// LoadFile add messages from .json, .yaml or .yml file named by its locale, e.g. "id.yaml" or "en-US.json".
+func (b *Bundle) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	ext := filepath.Ext(path)
+	return b.Load(file, strings.TrimSuffix(filepath.Base(path), ext), strings.TrimPrefix(ext, "."))
+}
+
+// LoadDir add every .json, .yaml and .yml file of dir, see LoadFile.
+func (b *Bundle) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if entry.IsDir() {
+				continue
+			}
+			if err = b.LoadFile(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Negotiate pick best locale of Accept-Language header having messages or fallback in bundle, q-values respected.
+// Region is dropped when only its language is available, default locale is returned when nothing match.
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	type languageRange struct {
+		tag string
+		q   float64
+	}
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		lr := languageRange{tag: strings.TrimSpace(fields[0]), q: 1}
+		for _, param := range fields[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(v, 64); err == nil {
+					lr.q = q
+				}
+			}
+		}
+		if lr.tag != "" && lr.q > 0 {
+			ranges = append(ranges, lr)
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, lr := range ranges {
+		if lr.tag == "*" {
+			return b.defaultLocale
+		}
+		for _, tag := range localeParents(canonicalLocale(lr.tag)) {
+			if _, ok := b.messages[tag]; ok {
+				return tag
+			}
+			if _, ok := b.fallbacks[tag]; ok {
+				return tag
+			}
+		}
+	}
+	return b.defaultLocale
+}
+
+// Message find message of code in locale, then its parent languages, its fallbacks and default locale.
+// Placeholders are filled from vars, plural form is chosen by vars["count"]. Message whose chosen form is empty
+// is skipped, ok is false when no locale has non-empty one.
+func (b *Bundle) Message(locale string, code string, vars Vars) (string, bool) {
+	text, _, ok := b.lookup(locale, code, vars)
+	return text, ok
+}
+
+// lookup is Message also returning locale the message is found in.
+func (b *Bundle) lookup(locale string, code string, vars Vars) (string, string, bool) {
+	locale = canonicalLocale(locale)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, tag := range b.chain(locale) {
+		if msg, ok := b.messages[tag][code]; ok {
+			count, counted := pluralCount(vars)
+			text := msg.Other
+			if counted {
+				text = msg.Form(pluralRule(tag)(count), count)
+			}
+			if text == "" {
+				continue
+			}
+			return fillPlaceholders(text, vars), tag, true
+		}
+	}
+	return "", "", false
+}
+
+// chain list locales searched for locale, without duplicate.
+func (b *Bundle) chain(locale string) []string {
+	var chain []string
+	seen := map[string]bool{}
+	add := func(tags ...string) {
+		for _, tag := range tags {
+			if !seen[tag] {
+				seen[tag] = true
+				chain = append(chain, tag)
+			}
+		}
+	}
+	for _, tag := range localeParents(locale) {
+		add(tag)
+		for _, fallback := range b.fallbacks[tag] {
+			add(localeParents(fallback)...)
+		}
+	}
+	add(localeParents(b.defaultLocale)...)
+	return chain
+}
+
+// pluralCount read "count" var as number.
+func pluralCount(vars Vars) (float64, bool) {
+	v, ok := vars["count"]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	if err != nil || math.IsNaN(n) {
+		return 0, false
+	}
+	return math.Abs(n), true
+}
+
+// canonicalLocale normalize locale like "en_us" into "en-US", language lower case,
+// 2 letters region upper case and 4 letters script title case.
+func canonicalLocale(locale string) string {
+	parts := strings.FieldsFunc(strings.TrimSpace(locale), func(r rune) bool { return r == '-' || r == '_' })
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeParents return locale followed by its shorter prefixes, "zh-Hant-TW" give "zh-Hant-TW", "zh-Hant", "zh".
+func localeParents(locale string) []string {
+	if locale == "" {
+		return nil
+	}
+	parents := []string{locale}
+	for i := strings.LastIndexByte(locale, '-'); i > 0; i = strings.LastIndexByte(locale, '-') {
+		locale = locale[:i]
+		parents = append(parents, locale)
+	}
+	return parents
+}
+
+// LoadMessageFile add messages from file into DefaultBundle, see Bundle.LoadFile.
+func LoadMessageFile(path string) error {
+	return DefaultBundle.LoadFile(path)
+}
+
+// LoadMessageDir add messages from every file of dir into DefaultBundle, see Bundle.LoadDir.
+func LoadMessageDir(dir string) error {
+	return DefaultBundle.LoadDir(dir)
+}
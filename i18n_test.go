@@ -0,0 +1,99 @@
+package jumper
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func testBundle() *Bundle {
+	b := NewBundle("en")
+	b.Add("en", map[string]Message{
+		"ITEMS_FOUND":    {Zero: "No item found", One: "{count} item found", Other: "{count} items found"},
+		"USER_NOT_FOUND": {Other: "User {id} not found"},
+		"ONLY_ONE":       {One: "One thing"},
+	})
+	b.Add("id", map[string]Message{
+		"ITEMS_FOUND":    {Other: "{count} barang ditemukan"},
+		"USER_NOT_FOUND": {Other: "Pengguna {id} tidak ditemukan"},
+	})
+	b.Add("en-GB", map[string]Message{"USER_NOT_FOUND": {Other: "User {id} could not be found"}})
+	b.SetFallback("ms", "id")
+	return b
+}
+
+func TestBundleNegotiate(t *testing.T) {
+	b := testBundle()
+	for header, want := range map[string]string{
+		"":                       "en",
+		"id":                     "id",
+		"fr, id;q=0.8, en;q=0.5": "id",
+		"en;q=0.5, id;q=0.9":     "id",
+		"en-GB":                  "en-GB",
+		"en-US":                  "en",
+		"id-ID":                  "id",
+		"ms":                     "ms",
+		"id;q=0, fr":             "en",
+		"de, *;q=0.1":            "en",
+	} {
+		if got := b.Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestBundleMessage(t *testing.T) {
+	b := testBundle()
+	for _, c := range []struct {
+		locale, code string
+		vars         Vars
+		want         string
+		ok           bool
+	}{
+		{"en", "ITEMS_FOUND", Vars{"count": 0}, "No item found", true},
+		{"en", "ITEMS_FOUND", Vars{"count": 1}, "1 item found", true},
+		{"en", "ITEMS_FOUND", Vars{"count": 3}, "3 items found", true},
+		{"id", "ITEMS_FOUND", Vars{"count": 1}, "1 barang ditemukan", true},
+		{"ms", "USER_NOT_FOUND", Vars{"id": 7}, "Pengguna 7 tidak ditemukan", true},
+		{"en-GB", "ITEMS_FOUND", Vars{"count": 2}, "2 items found", true},
+		{"de", "USER_NOT_FOUND", Vars{"id": 7}, "User 7 not found", true},
+		{"en", "ONLY_ONE", Vars{"count": 1}, "One thing", true},
+		{"en", "ONLY_ONE", Vars{"count": 2}, "", false},
+		{"en", "ONLY_ONE", nil, "", false},
+		{"en", "MISSING", nil, "", false},
+	} {
+		got, ok := b.Message(c.locale, c.code, c.vars)
+		if got != c.want || ok != c.ok {
+			t.Errorf("Message(%s, %s, %v) = %q, %t, want %q, %t", c.locale, c.code, c.vars, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestReplyLocalized(t *testing.T) {
+	defer func(b *Bundle) { DefaultBundle = b }(DefaultBundle)
+	DefaultBundle = testBundle()
+
+	for _, c := range []struct {
+		language, code, want, contentLanguage string
+	}{
+		{"id-ID,id;q=0.9", "USER_NOT_FOUND", "Pengguna 7 tidak ditemukan", "id"},
+		{"fr", "USER_NOT_FOUND", "User 7 not found", "en"},
+		{"en", "ONLY_ONE", "Fallback message", ""},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", c.language)
+		w := httptest.NewRecorder()
+		res := PlugResponseFor(w, r).WithVars(Vars{"id": 7, "count": 2})
+		if err := res.ReplyFailed("4040001", c.code, "Fallback message"); err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Message string `json:"status_message"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if body.Message != c.want || w.Header().Get("Content-Language") != c.contentLanguage {
+			t.Errorf("%s %s: status_message = %q, Content-Language = %q, want %q, %q",
+				c.language, c.code, body.Message, w.Header().Get("Content-Language"), c.want, c.contentLanguage)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package jumper
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 )
 
@@ -24,3 +28,105 @@ func (n Number) Int64() int64 {
 	i, _ := strconv.ParseInt(n.String(), 10, 64)
 	return i
 }
+
+// ExactNumber keep numeric literal exactly as received, it accept JSON number or numeric string.
+// Use it instead of Number for 64-bit identifiers and amounts that float64 cannot hold.
+type ExactNumber string
+
+func (n ExactNumber) String() string {
+	if n == "" {
+		return "0"
+	}
+	return string(n)
+}
+
+func (n ExactNumber) Float64() float64 {
+	f, _ := strconv.ParseFloat(n.String(), 64)
+	return f
+}
+
+// Int64 return exact integer, fractional part is truncated and out of range value is clamped.
+func (n ExactNumber) Int64() int64 {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i
+	}
+	if b, ok := n.BigInt(); ok {
+		if b.IsInt64() {
+			return b.Int64()
+		} else if b.Sign() < 0 {
+			return -1 << 63
+		}
+		return 1<<63 - 1
+	}
+	return 0
+}
+
+// Uint64 return exact unsigned integer, fractional part is truncated and out of range value is clamped.
+func (n ExactNumber) Uint64() uint64 {
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return u
+	}
+	if b, ok := n.BigInt(); ok {
+		if b.IsUint64() {
+			return b.Uint64()
+		} else if b.Sign() > 0 {
+			return 1<<64 - 1
+		}
+	}
+	return 0
+}
+
+// BigInt return integer part of the literal without precision loss.
+func (n ExactNumber) BigInt() (*big.Int, bool) {
+	if b, ok := new(big.Int).SetString(n.String(), 10); ok {
+		return b, true
+	}
+	r, ok := n.Rat()
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).Quo(r.Num(), r.Denom()), true
+}
+
+// Rat return the literal as exact rational number.
+func (n ExactNumber) Rat() (*big.Rat, bool) {
+	return new(big.Rat).SetString(n.String())
+}
+
+// IsInteger report whether literal has no fractional part.
+func (n ExactNumber) IsInteger() bool {
+	r, ok := n.Rat()
+	return ok && r.IsInt()
+}
+
+func isNumberLiteral(s string) bool {
+	return s != "" && (s[0] == '-' || (s[0] >= '0' && s[0] <= '9')) && json.Valid([]byte(s))
+}
+
+func (n ExactNumber) MarshalJSON() ([]byte, error) {
+	if !isNumberLiteral(n.String()) {
+		return nil, errors.New("invalid number literal " + strconv.Quote(string(n)))
+	}
+	return []byte(n.String()), nil
+}
+
+func (n *ExactNumber) UnmarshalJSON(data []byte) error {
+	if n == nil {
+		return errors.New("null point exception")
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	if !isNumberLiteral(string(data)) {
+		return errors.New("invalid number literal " + strconv.Quote(string(data)))
+	}
+	*n = ExactNumber(data)
+	return nil
+}
@@ -0,0 +1,32 @@
+package jumper
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNumberFloatBacked(t *testing.T) {
+	n := Number(3.5) * 2
+	if n.Float64() != 7 || n.Int64() != 7 || n.String() != "7" {
+		t.Errorf("Number(7) = %v, %v, %q", n.Float64(), n.Int64(), n.String())
+	}
+}
+
+func TestExactNumber(t *testing.T) {
+	var v struct {
+		ID    ExactNumber `json:"id"`
+		Price ExactNumber `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"id":9007199254740993,"price":"19.99"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.ID.Int64() != 9007199254740993 || v.ID.Uint64() != 9007199254740993 {
+		t.Errorf("ID = %d, %d, want 9007199254740993", v.ID.Int64(), v.ID.Uint64())
+	}
+	if d, err := v.Price.Decimal(); err != nil || d.String() != "19.99" {
+		t.Errorf("Price.Decimal() = %v, %v", d, err)
+	}
+	if out, _ := json.Marshal(v); string(out) != `{"id":9007199254740993,"price":19.99}` {
+		t.Errorf("Marshal() = %s", out)
+	}
+}
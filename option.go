@@ -0,0 +1,134 @@
+package jumper
+
+import (
+	"time"
+)
+
+// SniffJSON decide whether query and form value looking like JSON array or object is decoded into params,
+// use WithJSONSniffing or WithJSONFields to override per request.
+var SniffJSON = true
+
+// FloatNumbers restore float64 numbers in params instead of json.Number, for handler asserting params["x"].(float64).
+// Integers above 2^53 lose precision, use WithFloatNumbers to override per request.
+var FloatNumbers = false
+
+// DefaultMaxMultipartMemory is memory used by multipart form parser before spilling files to disk.
+var DefaultMaxMultipartMemory int64 = 32 << 10
+
+// RequestOption configure how PlugRequestWith parse incoming request.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	maxBodySize        int64
+	maxMultipartMemory int64
+	maxFiles           int
+	maxFileSize        int64
+	replayBody         bool
+	strict             bool
+	timeLayouts        []string
+	location           *time.Location
+	sniffJSON          bool
+	jsonFields         map[string]bool
+	streamMultipart    bool
+	floatNumbers       bool
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{
+		maxMultipartMemory: DefaultMaxMultipartMemory,
+		sniffJSON:          SniffJSON,
+		floatNumbers:       FloatNumbers,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithMaxBodySize limit request body to n bytes, 0 means unlimited.
+func WithMaxBodySize(n int64) RequestOption {
+	return func(c *requestConfig) {
+		c.maxBodySize = n
+	}
+}
+
+// WithMaxMultipartMemory set memory used by multipart form parser before spilling files to disk.
+func WithMaxMultipartMemory(n int64) RequestOption {
+	return func(c *requestConfig) {
+		c.maxMultipartMemory = n
+	}
+}
+
+// WithMaxFiles limit number of uploaded files, 0 means unlimited.
+func WithMaxFiles(n int) RequestOption {
+	return func(c *requestConfig) {
+		c.maxFiles = n
+	}
+}
+
+// WithMaxFileSize limit size of each uploaded file to n bytes, 0 means unlimited.
+func WithMaxFileSize(n int64) RequestOption {
+	return func(c *requestConfig) {
+		c.maxFileSize = n
+	}
+}
+
+// WithBodyReplay buffer request body so handler can read it again after parsing, see TouchRequest.
+func WithBodyReplay() RequestOption {
+	return func(c *requestConfig) {
+		c.replayBody = true
+	}
+}
+
+// WithStrict enable strict conversion on getters, see Request.SetStrict.
+func WithStrict() RequestOption {
+	return func(c *requestConfig) {
+		c.strict = true
+	}
+}
+
+// WithTimeLayouts replace TimeLayouts tried by GetTime, see Request.SetTimeLayouts.
+func WithTimeLayouts(layouts ...string) RequestOption {
+	return func(c *requestConfig) {
+		c.timeLayouts = layouts
+	}
+}
+
+// WithLocation set location applied to time value without zone instead of DefaultLocation.
+func WithLocation(loc *time.Location) RequestOption {
+	return func(c *requestConfig) {
+		c.location = loc
+	}
+}
+
+// WithJSONSniffing enable or disable decoding JSON array and object carried by query and form value, see SniffJSON.
+func WithJSONSniffing(enabled bool) RequestOption {
+	return func(c *requestConfig) {
+		c.sniffJSON = enabled
+	}
+}
+
+// WithFloatNumbers enable or disable float64 numbers in params instead of json.Number, see FloatNumbers.
+func WithFloatNumbers(enabled bool) RequestOption {
+	return func(c *requestConfig) {
+		c.floatNumbers = enabled
+	}
+}
+
+// WithJSONFields limit JSON sniffing to given query and form keys, other values are always kept as string.
+func WithJSONFields(keys ...string) RequestOption {
+	return func(c *requestConfig) {
+		c.jsonFields = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			c.jsonFields[key] = true
+		}
+	}
+}
+
+// WithStreamingMultipart defer multipart parsing to Request.EachPart, so file parts are read straight from body
+// instead of being buffered in memory or temp files. Field parts are collected into params while iterating.
+func WithStreamingMultipart() RequestOption {
+	return func(c *requestConfig) {
+		c.streamMultipart = true
+	}
+}
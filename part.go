@@ -0,0 +1,230 @@
+package jumper
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotStreamed is returned by EachPart when request was not parsed with WithStreamingMultipart,
+// or its parts were already consumed.
+var ErrNotStreamed = errors.New("multipart body is not streamed")
+
+// maxFieldBytes limit total size of field parts collected by EachPart, like net/http does for ParseMultipartForm.
+const maxFieldBytes int64 = 10 << 20
+
+// maxParts limit number of parts read by EachPart, like mime/multipart does for ReadForm.
+const maxParts = 1000
+
+// parseMultipartForm parse multipart body into r.MultipartForm like http.Request.ParseMultipartForm.
+// WithMaxFiles and WithMaxFileSize are enforced while parts are read: parts are copied through pipe into
+// multipart.Reader.ReadForm and copying stop at the first exceeded limit, so the rest of body is never spooled.
+func parseMultipartForm(r *http.Request, cfg *requestConfig) error {
+	if cfg.maxFiles <= 0 && cfg.maxFileSize <= 0 {
+		return r.ParseMultipartForm(cfg.maxMultipartMemory)
+	}
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	parts, err := r.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	copied := make(chan error, 1)
+	go func() {
+		err := copyParts(parts, mw, cfg)
+		pw.CloseWithError(err)
+		copied <- err
+	}()
+	form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(cfg.maxMultipartMemory)
+	pr.Close()
+	if copyErr := <-copied; copyErr != nil && copyErr != io.ErrClosedPipe {
+		err = copyErr
+	}
+	if err != nil {
+		if form != nil {
+			_ = form.RemoveAll()
+		}
+		return err
+	}
+
+	if r.PostForm == nil {
+		r.PostForm = url.Values{}
+	}
+	for k, v := range form.Value {
+		r.Form[k] = append(r.Form[k], v...)
+		r.PostForm[k] = append(r.PostForm[k], v...)
+	}
+	r.MultipartForm = form
+	return nil
+}
+
+// copyParts copy every part of parts into mw, failing with ErrBodyTooLarge as soon as file limit is exceeded.
+func copyParts(parts *multipart.Reader, mw *multipart.Writer, cfg *requestConfig) error {
+	files := 0
+	for {
+		p, err := parts.NextPart()
+		if err == io.EOF {
+			return mw.Close()
+		} else if err != nil {
+			return err
+		}
+		if p.FormName() == "" {
+			continue
+		}
+
+		var src io.Reader = p
+		if p.FileName() != "" {
+			if files++; cfg.maxFiles > 0 && files > cfg.maxFiles {
+				return &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("%d files exceed limit of %d", files, cfg.maxFiles)}
+			}
+			if cfg.maxFileSize > 0 {
+				src = io.LimitReader(p, cfg.maxFileSize+1)
+			}
+		}
+		w, err := mw.CreatePart(p.Header)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(w, src)
+		if err != nil {
+			return err
+		}
+		if p.FileName() != "" && cfg.maxFileSize > 0 && n > cfg.maxFileSize {
+			return &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("file %q exceed %d bytes", p.FileName(), cfg.maxFileSize)}
+		}
+	}
+}
+
+// Part is file part of streamed multipart body, its content is read directly from request body.
+type Part struct {
+	p     *multipart.Part
+	read  int64
+	limit int64
+}
+
+func (p *Part) FormName() string {
+	return p.p.FormName()
+}
+
+func (p *Part) FileName() string {
+	return p.p.FileName()
+}
+
+func (p *Part) Header() textproto.MIMEHeader {
+	return p.p.Header
+}
+
+func (p *Part) ContentType() string {
+	return p.p.Header.Get("Content-Type")
+}
+
+// Size return number of bytes read from part so far.
+func (p *Part) Size() int64 {
+	return p.read
+}
+
+// Read read part content, reading beyond WithMaxFileSize fail with ErrBodyTooLarge.
+func (p *Part) Read(b []byte) (int, error) {
+	if p.limit > 0 && int64(len(b)) > p.limit-p.read+1 {
+		b = b[:p.limit-p.read+1]
+	}
+	n, err := p.p.Read(b)
+	p.read += int64(n)
+	if p.limit > 0 && p.read > p.limit {
+		return n, &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("file %q exceed %d bytes", p.FileName(), p.limit)}
+	}
+	return n, err
+}
+
+// CopyTo pipe part content into w.
+func (p *Part) CopyTo(w io.Writer) (int64, error) {
+	return io.Copy(w, p)
+}
+
+// Store write part content into new file created from pattern in path, like File.Store.
+func (p *Part) Store(path string, pattern string, perm os.FileMode) (string, error) {
+	name, err := storeTemp(p, path, pattern, perm)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(name), nil
+}
+
+// StoreAs write part content into path/name, like File.StoreAs.
+func (p *Part) StoreAs(path string, name string, perm os.FileMode) error {
+	return storeAs(p, path, name, perm)
+}
+
+// EachPart stream multipart body of request parsed with WithStreamingMultipart, fn is called for every file part
+// in order and may read it, pipe it or skip it. Field parts are collected into params, so field sent before a file
+// is readable from fn and every field is readable after EachPart return. Parts can be iterated only once.
+func (r *Request) EachPart(fn func(part *Part) error) error {
+	if r.parts == nil {
+		return ErrNotStreamed
+	}
+	parts, cfg := r.parts, r.config
+	r.parts = nil
+
+	files, fieldBytes := 0, int64(0)
+	for count := 1; ; count++ {
+		p, err := parts.NextPart()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return newRequestError(ErrMultipart, err)
+		}
+		if count > maxParts {
+			return &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("parts exceed limit of %d", maxParts)}
+		}
+
+		if p.FileName() == "" {
+			if p.FormName() == "" {
+				continue
+			}
+			value, err := io.ReadAll(io.LimitReader(p, maxFieldBytes-fieldBytes+1))
+			if err != nil {
+				return newRequestError(ErrMultipart, err)
+			}
+			if fieldBytes += int64(len(value)); fieldBytes > maxFieldBytes {
+				return &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("fields exceed %d bytes", maxFieldBytes)}
+			}
+			r.addField(p.FormName(), string(value))
+			continue
+		}
+
+		if files++; cfg.maxFiles > 0 && files > cfg.maxFiles {
+			return &RequestError{Kind: ErrBodyTooLarge, Err: fmt.Errorf("%d files exceed limit of %d", files, cfg.maxFiles)}
+		}
+		if err = fn(&Part{p: p, limit: cfg.maxFileSize}); err != nil {
+			return err
+		}
+	}
+}
+
+// addField append streamed field value and update params of its key only, following the same rules as setParams.
+func (r *Request) addField(key string, value string) {
+	r.form[key] = append(r.form[key], value)
+	segments, ok := bracketSegments(key)
+	if !ok {
+		if _, nested := r.fields[key]; !nested {
+			r.params[key] = scan(r.form[key], r.sniffs(key))
+		}
+	} else {
+		r.expandKey(key, segments, []string{value})
+		key = segments[0]
+		r.params[key] = indexLists(r.fields[key])
+	}
+	if v, ok := r.params[key]; ok && r.config != nil && r.config.floatNumbers {
+		r.params[key] = floatNumbers(v)
+	}
+}
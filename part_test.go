@@ -0,0 +1,97 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func streamRequest(t *testing.T, write func(w *multipart.Writer)) *Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	write(w)
+	w.Close()
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	req, err := ParseRequest(r, WithStreamingMultipart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestEachPartFields(t *testing.T) {
+	const query = "items[0][q]=1&items[][q]=2&items[][name]=b&items[][q]=3&user=abc&user[name]=x&tags[]=a&tags[]=b"
+	req := streamRequest(t, func(w *multipart.Writer) {
+		for _, pair := range strings.Split(query, "&") {
+			key, value, _ := strings.Cut(pair, "=")
+			w.WriteField(key, value)
+		}
+		fw, _ := w.CreateFormFile("file", "a.txt")
+		fw.Write([]byte("content"))
+	})
+	var title string
+	err := req.EachPart(func(part *Part) error {
+		title = req.GetString("user.name")
+		_, err := io.Copy(io.Discard, part)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "x" {
+		t.Errorf("field before file = %q, want x", title)
+	}
+	got, _ := json.Marshal(req.GetAll())
+	want, _ := json.Marshal(parseQuery(t, query).GetAll())
+	if string(got) != string(want) {
+		t.Errorf("GetAll() = %s, want %s", got, want)
+	}
+}
+
+func TestEachPartMaxParts(t *testing.T) {
+	req := streamRequest(t, func(w *multipart.Writer) {
+		for i := 0; i < 8000; i++ {
+			w.WriteField("a[]", "")
+		}
+	})
+	start := time.Now()
+	err := req.EachPart(func(part *Part) error { return nil })
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("EachPart() = %v, want ErrBodyTooLarge", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("EachPart() took %s", elapsed)
+	}
+}
+
+func TestEachPartFloatNumbers(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("filter", `{"min":5}`)
+	w.WriteField("range[max]", `[9]`)
+	w.Close()
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	req, err := ParseRequest(r, WithStreamingMultipart(), WithFloatNumbers(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.EachPart(func(*Part) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(req.GetAll())
+	if _, ok := req.GetAll()["filter"].(map[string]interface{})["min"].(float64); !ok || string(got) != `{"filter":{"min":5},"range":{"max":[9]}}` {
+		t.Errorf("GetAll() = %s, want float64 numbers", got)
+	}
+	if max, ok := req.GetAll()["range"].(map[string]interface{})["max"].([]interface{}); !ok || max[0] != 9.0 {
+		t.Errorf("range.max = %#v, want float64 item", req.GetAll()["range"])
+	}
+}
@@ -0,0 +1,84 @@
+package jumper
+
+import (
+	"strconv"
+	"strings"
+)
+
+// lookup resolve key from params. Key not found literally is walked as path into nested
+// objects and arrays, e.g. "user.address.city", "items[0].id" or "matrix[1][2]".
+func (r *Request) lookup(key string) (interface{}, bool) {
+	if v, ok := r.params[key]; ok {
+		return v, true
+	}
+	segments, ok := parsePath(key)
+	if !ok || len(segments) < 2 {
+		return nil, false
+	}
+
+	var current interface{} = map[string]interface{}(r.params)
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if current, ok = node[segment]; !ok {
+				return nil, false
+			}
+		case Params:
+			if current, ok = node[segment]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// parsePath split path into keys, bracket content may be quoted to keep dots, e.g. meta["a.b"].
+func parsePath(path string) ([]string, bool) {
+	var segments []string
+	var current strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '.':
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			} else if i == 0 || path[i-1] != ']' {
+				return nil, false
+			}
+		case '[':
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, false
+			}
+			segment := path[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(segment); err == nil {
+				segment = unquoted
+			}
+			if segment == "" {
+				return nil, false
+			}
+			segments = append(segments, segment)
+			i += end
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+	} else if strings.HasSuffix(path, ".") {
+		return nil, false
+	}
+	return segments, len(segments) > 0
+}
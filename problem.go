@@ -0,0 +1,151 @@
+package jumper
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Envelope is shape of failed reply, see DefaultEnvelope and Response.SetEnvelope.
+type Envelope int
+
+const (
+	// EnvelopeStatus reply jumper envelope with status, status_number, status_code and status_message.
+	EnvelopeStatus Envelope = iota
+	// EnvelopeProblem reply failed Reply* as RFC 9457 Problem Details, success reply keep jumper envelope.
+	EnvelopeProblem
+)
+
+// DefaultEnvelope is envelope of every Response unless changed by SetEnvelope.
+var DefaultEnvelope = EnvelopeStatus
+
+// ProblemTypeBase is prefixed to status code to build problem "type", e.g. "https://api.example.com/problems/".
+// Empty means "about:blank".
+var ProblemTypeBase = ""
+
+// ProblemMediaType is content type of Problem replied as JSON.
+const ProblemMediaType = "application/problem+json"
+
+// DefaultProblemStatus is HTTP status of Problem replied without status, e.g. ReplyFailed without SetHttpStatusCode.
+var DefaultProblemStatus = http.StatusBadRequest
+
+// Problem is RFC 9457 (formerly RFC 7807) Problem Details, Extensions are written as top level members.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	if p.Type == "" {
+		members["type"] = "about:blank"
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	} else if p.Status != 0 {
+		members["title"] = http.StatusText(p.Status)
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// SetEnvelope change envelope of this response only.
+func (r *ResponseX) SetEnvelope(envelope Envelope) *ResponseX {
+	r.envelope = &envelope
+	return r
+}
+
+func (r *ResponseX) envelopeOf() Envelope {
+	if r.envelope != nil {
+		return *r.envelope
+	}
+	return DefaultEnvelope
+}
+
+// reply send r as jumper envelope, or as Problem when failed reply is in EnvelopeProblem.
+func (r *ResponseX) reply(httpStatusCode int) error {
+	if r.Status != 0 || r.envelopeOf() != EnvelopeProblem {
+		return r.send(httpStatusCode, r)
+	}
+	if httpStatusCode == 0 {
+		httpStatusCode = r.httpStatusCode
+	}
+	return r.ReplyProblem(r.problem(httpStatusCode))
+}
+
+// problem map failed envelope into Problem, status number and code, data and errors become extensions.
+func (r *ResponseX) problem(httpStatusCode int) Problem {
+	p := Problem{
+		Type:   "about:blank",
+		Status: httpStatusCode,
+		Detail: r.StatusMessage,
+		Extensions: map[string]any{
+			"status_number": r.StatusNumber,
+			"status_code":   r.StatusCode,
+		},
+	}
+	if ProblemTypeBase != "" && r.StatusCode != "" {
+		p.Type = ProblemTypeBase + r.StatusCode
+	}
+	if r.Data != nil {
+		p.Extensions["data"] = r.Data
+	}
+	if r.Errors != nil {
+		p.Extensions["errors"] = r.Errors
+	}
+	return p
+}
+
+// ReplyProblem reply Problem Details regardless of envelope, JSON is written as application/problem+json.
+// Status is used as HTTP status code, DefaultProblemStatus when zero, and Instance default to request path.
+func (r *ResponseX) ReplyProblem(problem Problem) error {
+	if problem.Instance == "" {
+		problem.Instance = r.instance
+	}
+	if problem.Status == 0 {
+		problem.Status = DefaultProblemStatus
+	}
+	mediaType, encode := negotiateProblem(r.accept)
+	if encode == nil {
+		return r.send(0, nil)
+	}
+	if r.accept != "" {
+		r.w.Header().Add("Vary", "Accept")
+	}
+	r.w.Header().Set("Content-Type", mediaType)
+	r.w.WriteHeader(problem.Status)
+	return encode(r.w, problem)
+}
+
+// negotiateProblem pick encoder like negotiate, JSON is written as application/problem+json
+// and accepting application/problem+json is accepting JSON.
+func negotiateProblem(accept string) (string, Encoder) {
+	mediaType, encode := negotiate(accept)
+	if mediaType == "application/json" {
+		return ProblemMediaType, encode
+	}
+	if strings.TrimSpace(accept) == "" {
+		return mediaType, encode
+	}
+	ranges := acceptRanges(accept)
+	if q := quality(ranges, ProblemMediaType); q > 0 && (encode == nil || q > quality(ranges, mediaType)) {
+		return ProblemMediaType, encodeJSON
+	}
+	return mediaType, encode
+}
@@ -0,0 +1,52 @@
+package jumper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReplyFailedProblemStatus(t *testing.T) {
+	for accept, mediaType := range map[string]string{
+		"":                         ProblemMediaType,
+		"application/problem+json": ProblemMediaType,
+		"application/problem+json, application/xml;q=0.5": ProblemMediaType,
+		"application/xml, application/problem+json;q=0.5": "application/xml",
+	} {
+		r := httptest.NewRequest("POST", "/users", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		res := PlugResponseFor(w, r).SetEnvelope(EnvelopeProblem)
+		if err := res.ReplyFailed("4000001", "EMAIL_TAKEN", "Email is already registered"); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != DefaultProblemStatus || w.Header().Get("Content-Type") != mediaType {
+			t.Errorf("%q: reply %d %s, want %d %s", accept, w.Code, w.Header().Get("Content-Type"), DefaultProblemStatus, mediaType)
+		}
+		if mediaType != ProblemMediaType {
+			continue
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body["status"] != float64(http.StatusBadRequest) || body["title"] != "Bad Request" || body["instance"] != "/users" {
+			t.Errorf("%q: body = %v", accept, body)
+		}
+	}
+}
+
+func TestReplyProblemStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	res := PlugResponseFor(w, nil).SetEnvelope(EnvelopeProblem)
+	res.SetHttpStatusCode(409)
+	if err := res.ReplyFailed("4090001", "EMAIL_TAKEN", "taken"); err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]any
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusConflict || body["status"] != float64(http.StatusConflict) {
+		t.Errorf("problem reply %d with status %v, want 409", w.Code, body["status"])
+	}
+}
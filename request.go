@@ -8,10 +8,10 @@ import (
 	"git.verzth.work/go/utils"
 	"github.com/gorilla/mux"
 	"io"
-	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -19,159 +19,217 @@ import (
 )
 
 type Request struct {
-	r          http.Request
-	segments   map[string]string
-	params     Params
-	files      map[string]interface{}
-	header     http.Header
-	Method     string
-	ClientIP   string
-	ClientPort string
-}
+	r           http.Request
+	segments    map[string]string
+	params      Params
+	files       map[string]interface{}
+	query       url.Values
+	form        url.Values
+	header      http.Header
+	strict      bool
+	sniffJSON   bool
+	jsonFields  map[string]bool
+	parts       *multipart.Reader
+	fields      map[string]interface{}
+	rows        map[string]int
+	config      *requestConfig
+	timeLayouts []string
+	location    *time.Location
+	err         error
+	replied     bool
+	Method      string
+	ClientIP    string
+	ClientPort  string
+}
+
+// Envelope values replied by PlugRequestWith when body or upload exceed configured limits.
+var (
+	PayloadTooLargeStatusNumber  = "4130000"
+	PayloadTooLargeStatusCode    = "PAYLOAD_TOO_LARGE"
+	PayloadTooLargeStatusMessage = "Request entity too large"
+)
 
-func PlugRequest(r *http.Request, w http.ResponseWriter) *Request {
-	req := &Request{
+func newRequest(r *http.Request) *Request {
+	return &Request{
 		r:          *r,
 		segments:   mux.Vars(r),
 		params:     Params{},
 		files:      map[string]interface{}{},
+		query:      r.URL.Query(),
+		form:       url.Values{},
 		header:     r.Header,
 		Method:     r.Method,
 		ClientIP:   getHost(r),
 		ClientPort: getPort(r),
 	}
+}
 
-	// PARSE QUERY STRING PARAMETERS
-	for k, v := range r.URL.Query() {
-		req.params[k] = scan(v)
-	}
-
-	switch r.Method {
-	case http.MethodGet, "FETCH", http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch:
-		{
-			contentType := req.header.Get("Content-Type")
-			if strings.Contains(contentType, "multipart/form-data") {
-				if r.Method == http.MethodGet {
-					return req
-				}
-				err := r.ParseMultipartForm(32 << 10)
-				if err != nil {
-					w.WriteHeader(http.StatusBadRequest)
-					return req
-				}
-				for k, v := range r.MultipartForm.Value {
-					req.params[k] = scan(v)
-				}
-				for k, v := range r.MultipartForm.File {
-					req.files[k] = scanFiles(v)
-				}
-			} else if strings.Contains(contentType, "application/x-www-form-urlencoded") {
-				if r.Method == http.MethodGet {
-					return req
-				}
-				err := r.ParseForm()
-				if err != nil {
-					w.WriteHeader(http.StatusBadRequest)
-					return req
-				}
-				for k, v := range r.PostForm {
-					req.params[k] = scan(v)
-				}
-			} else if strings.Contains(contentType, "application/json") {
-				if r.ContentLength > 0 {
-					dec := json.NewDecoder(r.Body)
-
-					err := dec.Decode(&req.params)
-					if err != nil {
-						w.WriteHeader(http.StatusInternalServerError)
-						return req
-					}
-				}
-			}
-			break
-		}
-	}
+func PlugRequest(r *http.Request, w http.ResponseWriter) *Request {
+	return PlugRequestWith(r, w)
+}
+
+// PlugRequestWith plug request with limits from options, see WithMaxBodySize, WithMaxMultipartMemory,
+// WithMaxFiles and WithMaxFileSize. Exceeded limit is replied with 413 envelope and other parse failure with
+// 400 status, handler must return when Replied is true. Malformed JSON body keep 500 status replied by PlugRequest
+// before limits were added, while RequestError.HttpStatusCode and ReplyError suggest 400 for it:
+//
+//	req := jumper.PlugRequestWith(r, w, jumper.WithMaxBodySize(1<<20))
+//	if req.Replied() {
+//		return
+//	}
+//
+// Use ParseRequest to handle errors manually.
+func PlugRequestWith(r *http.Request, w http.ResponseWriter, opts ...RequestOption) *Request {
+	req, err := ParseRequest(r, opts...)
+	req.err = err
+	switch {
+	case err == nil, errors.Is(err, ErrUnsupportedMediaType):
+		return req
+	case errors.Is(err, ErrBodyTooLarge):
+		replyTooLarge(w)
+	case errors.Is(err, ErrMalformedJSON):
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	req.replied = true
 	return req
 }
 
-// TouchRequest touch request with rewrite to reader, so handler can reuse the reader.
-func TouchRequest(r *http.Request, w http.ResponseWriter) *Request {
-	req := &Request{
-		r:          *r,
-		segments:   mux.Vars(r),
-		params:     Params{},
-		files:      map[string]interface{}{},
-		header:     r.Header,
-		Method:     r.Method,
-		ClientIP:   getHost(r),
-		ClientPort: getPort(r),
+// Err return failure of parsing request by PlugRequest, PlugRequestWith or TouchRequest, see ParseRequest.
+func (r *Request) Err() error {
+	return r.err
+}
+
+// Replied report whether PlugRequestWith already wrote response for parse failure, so handler must not reply again.
+func (r *Request) Replied() bool {
+	return r.replied
+}
+
+// ParseRequest parse request like PlugRequestWith but return the failure instead of writing response.
+// Returned error is *RequestError matching one of ErrMalformedJSON, ErrMalformedBody, ErrMalformedForm, ErrMultipart,
+// ErrUnsupportedMediaType or ErrBodyTooLarge with errors.Is, Request is always returned with params parsed so far.
+func ParseRequest(r *http.Request, opts ...RequestOption) (*Request, error) {
+	cfg := newRequestConfig(opts)
+	req := newRequest(r)
+	req.strict = cfg.strict
+	req.sniffJSON = cfg.sniffJSON
+	req.jsonFields = cfg.jsonFields
+	req.timeLayouts = cfg.timeLayouts
+	req.location = cfg.location
+	if cfg.floatNumbers {
+		defer floatParams(req.params)
 	}
 
 	// PARSE QUERY STRING PARAMETERS
-	for k, v := range r.URL.Query() {
-		req.params[k] = scan(v)
+	req.setParams(req.query)
+
+	if cfg.maxBodySize > 0 {
+		if r.ContentLength > cfg.maxBodySize {
+			return req, &RequestError{Kind: ErrBodyTooLarge, Err: &http.MaxBytesError{Limit: cfg.maxBodySize}}
+		}
+		r.Body = http.MaxBytesReader(nil, r.Body, cfg.maxBodySize)
+	}
+
+	if cfg.replayBody && r.Body != nil && r.Body != http.NoBody {
+		buf, err := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			return req, newRequestError(ErrMalformedBody, err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(buf))
+		defer func() {
+			r.Body = io.NopCloser(bytes.NewReader(buf))
+			req.r.Body = io.NopCloser(bytes.NewReader(buf))
+		}()
 	}
 
 	switch r.Method {
 	case http.MethodGet, "FETCH", http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch:
-		{
-			contentType := req.header.Get("Content-Type")
-			if strings.Contains(contentType, "multipart/form-data") {
-				if r.Method == http.MethodGet {
-					return req
-				}
-				err := r.ParseMultipartForm(32 << 10)
-				if err != nil {
-					w.WriteHeader(http.StatusBadRequest)
-					return req
-				}
-				for k, v := range r.MultipartForm.Value {
-					req.params[k] = scan(v)
-				}
-				for k, v := range r.MultipartForm.File {
-					req.files[k] = scanFiles(v)
-				}
-			} else if strings.Contains(contentType, "application/x-www-form-urlencoded") {
-				if r.Method == http.MethodGet {
-					return req
-				}
-				err := r.ParseForm()
-				if err != nil {
-					w.WriteHeader(http.StatusBadRequest)
-					return req
-				}
-				for k, v := range r.PostForm {
-					req.params[k] = scan(v)
-				}
-			} else if strings.Contains(contentType, "application/json") {
-				if r.ContentLength > 0 {
-					b := bytes.NewBuffer(make([]byte, 0))
-					reader := io.TeeReader(r.Body, b)
-
-					dec := json.NewDecoder(reader)
-
-					err := dec.Decode(&req.params)
+		return req, req.parseBody(r, cfg)
+	}
+	return req, nil
+}
 
-					r.Body = ioutil.NopCloser(b)
-					if err != nil {
-						w.WriteHeader(http.StatusInternalServerError)
-						return req
-					}
-				}
+func (req *Request) parseBody(r *http.Request, cfg *requestConfig) error {
+	mediaType := mediaTypeOf(req.header.Get("Content-Type"))
+	switch mediaType {
+	case "":
+		return nil
+	case "multipart/form-data":
+		if r.Method == http.MethodGet {
+			return nil
+		}
+		if cfg.streamMultipart {
+			parts, err := r.MultipartReader()
+			if err != nil {
+				return newRequestError(ErrMultipart, err)
 			}
-			break
+			req.parts, req.config = parts, cfg
+			return nil
+		}
+		if err := parseMultipartForm(r, cfg); err != nil {
+			var reqErr *RequestError
+			if errors.As(err, &reqErr) {
+				return reqErr
+			}
+			return newRequestError(ErrMultipart, err)
+		}
+		for k, v := range r.MultipartForm.Value {
+			req.form[k] = v
+		}
+		req.setParams(r.MultipartForm.Value)
+		for k, v := range r.MultipartForm.File {
+			req.files[k] = scanFiles(v)
+		}
+		return nil
+	case "application/x-www-form-urlencoded":
+		if r.Method == http.MethodGet {
+			return nil
+		}
+		err := r.ParseForm()
+		if err != nil {
+			return newRequestError(ErrMalformedForm, err)
+		}
+		for k, v := range r.PostForm {
+			req.form[k] = v
 		}
+		req.setParams(r.PostForm)
+		return nil
 	}
-	return req
+
+	if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	decoder, decoderType := lookupDecoder(mediaType)
+	if decoder == nil {
+		return &RequestError{Kind: ErrUnsupportedMediaType, Err: fmt.Errorf("content type %q", mediaType)}
+	}
+	if err := decoder(r.Body, req.params); err != nil && err != io.EOF {
+		if decoderType == "application/json" {
+			return newRequestError(ErrMalformedJSON, err)
+		}
+		return newRequestError(ErrMalformedBody, err)
+	}
+	return nil
 }
 
-func scan(values []string) interface{} {
+func replyTooLarge(w http.ResponseWriter) {
+	_ = PlugResponse(w).ReplyAs(NewResponse(http.StatusRequestEntityTooLarge, 0, PayloadTooLargeStatusNumber, PayloadTooLargeStatusCode, PayloadTooLargeStatusMessage))
+}
+
+// TouchRequest touch request with rewrite to reader, so handler can reuse the reader.
+func TouchRequest(r *http.Request, w http.ResponseWriter) *Request {
+	return PlugRequestWith(r, w, WithBodyReplay())
+}
+
+func scan(values []string, sniff bool) interface{} {
 	if len(values) == 1 {
-		return identify(values[0])
+		return identify(values[0], sniff)
 	} else if len(values) > 1 {
-		list := []interface{}{}
+		list := make([]interface{}, len(values))
 		for k, vs := range values {
-			list[k] = identify(vs)
+			list[k] = identify(vs, sniff)
 		}
 		return list
 	} else {
@@ -179,11 +237,15 @@ func scan(values []string) interface{} {
 	}
 }
 
-func identify(value string) interface{} {
+// identify decode value carrying JSON array or object when sniff is enabled, otherwise keep it as string.
+func identify(value string, sniff bool) interface{} {
+	if !sniff {
+		return value
+	}
 	var arr []interface{}
 	var mp map[string]interface{}
-	errArr := json.Unmarshal([]byte(value), &arr)
-	errMp := json.Unmarshal([]byte(value), &mp)
+	errArr := unmarshalNumber([]byte(value), &arr)
+	errMp := unmarshalNumber([]byte(value), &mp)
 	if errArr == nil {
 		return arr
 	} else if errMp == nil {
@@ -278,7 +340,7 @@ func (r *Request) GetSegment(key string) string {
 
 func (r *Request) GetSegmentUint64(key string) uint64 {
 	if r.segments[key] != "" {
-		i64, _ := strconv.ParseUint(r.segments[key], 10, 32)
+		i64, _ := strconv.ParseUint(r.segments[key], 10, 64)
 		return i64
 	}
 	return 0
@@ -294,7 +356,7 @@ func (r *Request) GetSegmentUint(key string) uint {
 
 func (r *Request) GetSegmentInt64(key string) int64 {
 	if r.segments[key] != "" {
-		i64, _ := strconv.ParseInt(r.segments[key], 10, 32)
+		i64, _ := strconv.ParseInt(r.segments[key], 10, 64)
 		return i64
 	}
 	return 0
@@ -351,27 +413,28 @@ func (r *Request) GetAll() map[string]interface{} {
 	return r.params
 }
 
+// ParseTo bind params into new T and validate it against `validate` tags, see Validate.
 func ParseTo[T any](r *Request) (T, error) {
-	jsonString, _ := json.Marshal(r.params)
+	params := r.paramsOf(reflect.TypeOf((*T)(nil)).Elem())
+	jsonString, _ := json.Marshal(params)
 	var en T
 	err := json.Unmarshal(jsonString, &en)
-	if err != nil {
-		return en, err
-	} else {
-		return en, nil
-	}
+	return en, validateBound(&en, params, err)
 }
 
+// ParseOf bind params into en and validate it against `validate` tags, see Validate.
 func ParseOf[T any](r *Request, en *T) error {
-	jsonString, _ := json.Marshal(r.params)
+	params := r.paramsOf(reflect.TypeOf((*T)(nil)).Elem())
+	jsonString, _ := json.Marshal(params)
 	err := json.Unmarshal(jsonString, en)
-	return err
+	return validateBound(en, params, err)
 }
 
 func (r *Request) GetPtr(key string) *interface{} {
-	val := reflect.ValueOf(r.params[key])
-	if r.params[key] != nil || (val.IsValid() && val.Kind() == reflect.Interface) {
-		v := r.params[key]
+	param, _ := r.lookup(key)
+	val := reflect.ValueOf(param)
+	if param != nil || (val.IsValid() && val.Kind() == reflect.Interface) {
+		v := param
 		return &v
 	}
 	return nil
@@ -387,9 +450,10 @@ func (r *Request) Get(key string) interface{} {
 }
 
 func (r *Request) GetStringPtr(key string) *string {
-	val := reflect.ValueOf(r.params[key])
-	if r.params[key] != nil || (val.IsValid() && val.Kind() == reflect.Slice && val.Len() > 0) {
-		v := fmt.Sprintf("%v", r.params[key])
+	param, _ := r.lookup(key)
+	val := reflect.ValueOf(param)
+	if param != nil || (val.IsValid() && val.Kind() == reflect.Slice && val.Len() > 0) {
+		v := fmt.Sprintf("%v", param)
 		return &v
 	}
 	return nil
@@ -405,24 +469,7 @@ func (r *Request) GetString(key string) string {
 }
 
 func (r *Request) GetUint64Ptr(key string) *uint64 {
-	if r.params[key] != nil {
-		var v uint64
-		switch r.params[key].(type) {
-		case float64:
-			v = uint64(r.params[key].(float64))
-		case int:
-			v = uint64(r.params[key].(int))
-		case string:
-			v, _ = strconv.ParseUint(r.params[key].(string), 10, 32)
-		case bool:
-			{
-				if r.params[key].(bool) {
-					v = 1
-				} else {
-					v = 0
-				}
-			}
-		}
+	if v, err := r.GetUint64E(key); err == nil {
 		return &v
 	}
 	return nil
@@ -438,52 +485,31 @@ func (r *Request) GetUint64(key string) uint64 {
 }
 
 func (r *Request) GetUint32Ptr(key string) *uint32 {
-	v := r.GetUint64Ptr(key)
-	if v != nil {
-		val := uint32(*v)
-		return &val
-	} else {
-		return nil
+	if v, err := r.GetUint32E(key); err == nil {
+		return &v
 	}
+	return nil
 }
 
 func (r *Request) GetUint32(key string) uint32 {
-	return uint32(r.GetUint64(key))
+	v, _ := r.GetUint32E(key)
+	return v
 }
 
 func (r *Request) GetUintPtr(key string) *uint {
-	v := r.GetUint64Ptr(key)
-	if v != nil {
-		val := uint(*v)
-		return &val
-	} else {
-		return nil
+	if v, err := r.GetUintE(key); err == nil {
+		return &v
 	}
+	return nil
 }
 
 func (r *Request) GetUint(key string) uint {
-	return uint(r.GetUint64(key))
+	v, _ := r.GetUintE(key)
+	return v
 }
 
 func (r *Request) GetInt64Ptr(key string) *int64 {
-	if r.params[key] != nil {
-		var v int64
-		switch r.params[key].(type) {
-		case float64:
-			v = int64(r.params[key].(float64))
-		case int:
-			v = int64(r.params[key].(int))
-		case string:
-			v, _ = strconv.ParseInt(r.params[key].(string), 10, 32)
-		case bool:
-			{
-				if r.params[key].(bool) {
-					v = 1
-				} else {
-					v = 0
-				}
-			}
-		}
+	if v, err := r.GetInt64E(key); err == nil {
 		return &v
 	}
 	return nil
@@ -499,52 +525,31 @@ func (r *Request) GetInt64(key string) int64 {
 }
 
 func (r *Request) GetInt32Ptr(key string) *int32 {
-	v := r.GetUint64Ptr(key)
-	if v != nil {
-		val := int32(*v)
-		return &val
-	} else {
-		return nil
+	if v, err := r.GetInt32E(key); err == nil {
+		return &v
 	}
+	return nil
 }
 
 func (r *Request) GetInt32(key string) int32 {
-	return int32(r.GetInt64(key))
+	v, _ := r.GetInt32E(key)
+	return v
 }
 
 func (r *Request) GetIntPtr(key string) *int {
-	v := r.GetUint64Ptr(key)
-	if v != nil {
-		val := int(*v)
-		return &val
-	} else {
-		return nil
+	if v, err := r.GetIntE(key); err == nil {
+		return &v
 	}
+	return nil
 }
 
 func (r *Request) GetInt(key string) int {
-	return int(r.GetInt64(key))
+	v, _ := r.GetIntE(key)
+	return v
 }
 
 func (r *Request) GetFloat64Ptr(key string) *float64 {
-	if r.params[key] != nil {
-		var v float64
-		switch r.params[key].(type) {
-		case float64:
-			v = r.params[key].(float64)
-		case int:
-			v = float64(r.params[key].(int))
-		case string:
-			v, _ = strconv.ParseFloat(r.params[key].(string), 10)
-		case bool:
-			{
-				if r.params[key].(bool) {
-					v = 1
-				} else {
-					v = 0
-				}
-			}
-		}
+	if v, err := r.GetFloat64E(key); err == nil {
 		return &v
 	}
 	return nil
@@ -560,13 +565,10 @@ func (r *Request) GetFloat64(key string) float64 {
 }
 
 func (r *Request) GetFloat32Ptr(key string) *float32 {
-	v := r.GetFloat64Ptr(key)
-	if v != nil {
-		val := float32(*v)
-		return &val
-	} else {
-		return nil
+	if v, err := r.GetFloat32E(key); err == nil {
+		return &v
 	}
+	return nil
 }
 
 func (r *Request) GetFloat(key string) float32 {
@@ -574,19 +576,7 @@ func (r *Request) GetFloat(key string) float32 {
 }
 
 func (r *Request) GetBoolPtr(key string) *bool {
-	if r.params[key] != nil {
-		var v bool
-		switch r.params[key].(type) {
-		case float64:
-			v = r.params[key].(float64) > 0
-		case int:
-			v = float64(r.params[key].(int)) > 0
-		case string:
-			i64, _ := strconv.ParseFloat(r.params[key].(string), 10)
-			v = i64 > 0
-		case bool:
-			v = r.params[key].(bool)
-		}
+	if v, err := r.GetBoolE(key); err == nil {
 		return &v
 	}
 	return nil
@@ -601,22 +591,13 @@ func (r *Request) GetBool(key string) bool {
 	}
 }
 
+// GetTime get key value as *time.Time, see GetTimeE for accepted values.
 func (r *Request) GetTime(key string) (*time.Time, error) {
-	if r.params[key] != nil {
-		t, err := time.Parse(time.RFC3339, r.params[key].(string))
-		if err != nil {
-			t, err = time.Parse("2006-01-02T15:04:05.000Z07:00", r.params[key].(string)) // RFC3339Mili
-			if err != nil {
-				t, err = time.Parse(time.RFC3339Nano, r.params[key].(string))
-				if err != nil {
-					return nil, errors.New("use RFC3339 format string for datetime")
-				}
-			}
-		}
-		return &t, nil
-	} else {
-		return nil, errors.New("no time specified")
+	t, err := r.GetTimeE(key)
+	if err != nil {
+		return nil, err
 	}
+	return &t, nil
 }
 
 func (r *Request) GetTimeNE(key string) *time.Time {
@@ -625,8 +606,9 @@ func (r *Request) GetTimeNE(key string) *time.Time {
 }
 
 func (r *Request) GetArray(key string) []interface{} {
-	if r.params[key] != nil {
-		if v, ok := r.params[key].([]interface{}); ok {
+	param, _ := r.lookup(key)
+	if param != nil {
+		if v, ok := param.([]interface{}); ok {
 			return v
 		}
 	}
@@ -634,8 +616,9 @@ func (r *Request) GetArray(key string) []interface{} {
 }
 
 func (r *Request) GetArrayUniquify(key string) []interface{} {
-	if r.params[key] != nil {
-		if v, ok := r.params[key].([]interface{}); ok {
+	param, _ := r.lookup(key)
+	if param != nil {
+		if v, ok := param.([]interface{}); ok {
 			utils.Slice.Uniquify(&v)
 			return v
 		}
@@ -644,8 +627,9 @@ func (r *Request) GetArrayUniquify(key string) []interface{} {
 }
 
 func (r *Request) GetMap(key string) map[string]interface{} {
-	if r.params[key] != nil {
-		if v, ok := r.params[key].(map[string]interface{}); ok {
+	param, _ := r.lookup(key)
+	if param != nil {
+		if v, ok := param.(map[string]interface{}); ok {
 			return v
 		}
 	}
@@ -653,7 +637,8 @@ func (r *Request) GetMap(key string) map[string]interface{} {
 }
 
 func (r *Request) GetJSON(key string) JSON {
-	jsonObj, err := json.Marshal(r.params[key])
+	param, _ := r.lookup(key)
+	jsonObj, err := json.Marshal(param)
 	if err != nil {
 		return nil
 	} else {
@@ -667,7 +652,7 @@ func (r *Request) GetStruct(obj interface{}) error {
 }
 
 func (r *Request) has(key string) bool {
-	if _, found := r.params[key]; !found {
+	if _, found := r.lookup(key); !found {
 		return false
 	}
 	return true
@@ -685,7 +670,8 @@ func (r *Request) Filled(keys ...string) (found bool) {
 	found = true
 	for _, key := range keys {
 		found = found && r.has(key)
-		val := reflect.ValueOf(r.params[key])
+		param, _ := r.lookup(key)
+		val := reflect.ValueOf(param)
 		if val.IsValid() {
 			switch val.Kind() {
 			case reflect.String:
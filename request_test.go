@@ -0,0 +1,104 @@
+package jumper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPlugRequestWithReplied(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"abcdefghij"}`))
+	r.Header.Set("Content-Type", "application/json")
+	req := PlugRequestWith(r, w, WithMaxBodySize(8))
+	if !req.Replied() || !errors.Is(req.Err(), ErrBodyTooLarge) {
+		t.Fatalf("Replied() = %v, Err() = %v, want true and ErrBodyTooLarge", req.Replied(), req.Err())
+	}
+	if w.Code != 413 {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	if req = PlugRequestWith(r, w, WithMaxBodySize(8)); req.Replied() || req.Err() != nil {
+		t.Errorf("Replied() = %v, Err() = %v, want false and nil", req.Replied(), req.Err())
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += n
+	return n, err
+}
+
+func TestMultipartLimitsStopReading(t *testing.T) {
+	for name, opt := range map[string]RequestOption{
+		"max files":     WithMaxFiles(1),
+		"max file size": WithMaxFileSize(1 << 20),
+	} {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		w.WriteField("title", "report")
+		for i := 0; i < 4; i++ {
+			fw, _ := w.CreateFormFile("doc", fmt.Sprintf("%d.bin", i))
+			fw.Write(bytes.Repeat([]byte("x"), 2<<20))
+		}
+		w.Close()
+		total := body.Len()
+		cr := &countingReader{r: &body}
+		r := httptest.NewRequest("POST", "/", cr)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+
+		_, err := ParseRequest(r, opt)
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("%s: ParseRequest() = %v, want ErrBodyTooLarge", name, err)
+		}
+		if cr.n > total/2 {
+			t.Errorf("%s: read %d of %d bytes, want reading stopped at the limit", name, cr.n, total)
+		}
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("title", "report")
+	fw, _ := w.CreateFormFile("doc", "a.txt")
+	fw.Write([]byte("hello"))
+	w.Close()
+	r := httptest.NewRequest("POST", "/?page=1", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	req, err := ParseRequest(r, WithMaxFiles(1), WithMaxFileSize(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.GetString("title") != "report" || r.FormValue("title") != "report" || r.FormValue("page") != "1" {
+		t.Errorf("fields not parsed: title %q, form %v", req.GetString("title"), r.Form)
+	}
+	if f, err := req.GetFile("doc"); err != nil || f.GetFileHeader().Size != 5 {
+		t.Errorf("GetFile(doc) = %v", err)
+	}
+}
+
+func TestPlugRequestWithMalformedJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":`))
+	r.Header.Set("Content-Type", "application/json")
+	req := PlugRequestWith(r, w)
+	var reqErr *RequestError
+	if !req.Replied() || !errors.As(req.Err(), &reqErr) || !errors.Is(reqErr, ErrMalformedJSON) {
+		t.Fatalf("Replied() = %v, Err() = %v", req.Replied(), req.Err())
+	}
+	if w.Code != 500 || reqErr.HttpStatusCode() != 400 {
+		t.Errorf("status = %d, HttpStatusCode() = %d, want 500 kept from PlugRequest and 400 suggested", w.Code, reqErr.HttpStatusCode())
+	}
+}
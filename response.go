@@ -2,6 +2,7 @@ package jumper
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -23,14 +24,34 @@ type Response interface {
 
 type ResponseX struct {
 	w              http.ResponseWriter
+	accept         string
+	language       string
+	vars           Vars
+	instance       string
+	envelope       *Envelope
 	httpStatusCode int
 	Status         int    `json:"status"`
 	StatusNumber   string `json:"status_number"`
 	StatusCode     string `json:"status_code"`
 	StatusMessage  string `json:"status_message"`
 	Data           any    `json:"data"`
+	Errors         any    `json:"errors,omitempty"`
 }
 
+// Envelope values replied when no registered encoder satisfy Accept header.
+var (
+	NotAcceptableStatusNumber  = "4060000"
+	NotAcceptableStatusCode    = "NOT_ACCEPTABLE"
+	NotAcceptableStatusMessage = "Not acceptable"
+)
+
+// Envelope values used by ReplyValidation.
+var (
+	ValidationStatusNumber  = "4220000"
+	ValidationStatusCode    = "VALIDATION_ERROR"
+	ValidationStatusMessage = "Validation failed"
+)
+
 func NewResponse(httpStatusCode int, Status int, StatusNumber string, StatusCode string, StatusMessage string, Data ...any) Response {
 	rx := ResponseX{
 		Status:        Status,
@@ -75,6 +96,13 @@ func (r *ResponseX) GetData() any {
 }
 
 func PlugResponse(w http.ResponseWriter) Response {
+	return PlugResponseFor(w, nil)
+}
+
+// PlugResponseFor plug response writer replying in format negotiated from Accept header of r, see RegisterEncoder,
+// and in language from its Accept-Language, see Bundle. Nil r always reply JSON like PlugResponse.
+// Returned *ResponseX implement Response, and also has ReplyValidation, ReplyStatus, ReplyError and ReplyProblem.
+func PlugResponseFor(w http.ResponseWriter, r *http.Request) *ResponseX {
 	res := &ResponseX{
 		Status:        0,
 		StatusNumber:  "",
@@ -83,44 +111,92 @@ func PlugResponse(w http.ResponseWriter) Response {
 		Data:          nil,
 	}
 	res.w = w
+	if r != nil {
+		res.accept = r.Header.Get("Accept")
+		res.language = r.Header.Get("Accept-Language")
+		res.instance = r.URL.Path
+	}
 	return res
 }
 
+// WithVars set values of {name} placeholders and plural "count" used by next reply, see Bundle.
+func (r *ResponseX) WithVars(vars Vars) *ResponseX {
+	r.vars = vars
+	return r
+}
+
+// Locale return locale negotiated from Accept-Language header against DefaultBundle.
+func (r *ResponseX) Locale() string {
+	return DefaultBundle.Negotiate(r.language)
+}
+
+// localize return message of code in negotiated locale from DefaultBundle, given message is used when bundle doesn't have it.
+func (r *ResponseX) localize(code string, message string, vars Vars) string {
+	if DefaultBundle.empty() {
+		return fillPlaceholders(message, vars)
+	}
+	r.w.Header().Add("Vary", "Accept-Language")
+	text, locale, ok := DefaultBundle.lookup(r.Locale(), code, vars)
+	if !ok {
+		return fillPlaceholders(message, vars)
+	}
+	r.w.Header().Set("Content-Language", locale)
+	return text
+}
+
 func (r *ResponseX) SetHttpCode(code int) Response {
 	r.w.WriteHeader(code)
 	return r
 }
 
-func (r *ResponseX) ReplyAs(res Response) error {
-	r.w.Header().Set("Content-Type", "application/json")
-	if res.HttpStatusCode() != 0 {
-		r.w.WriteHeader(res.HttpStatusCode())
+func (r *ResponseX) send(httpStatusCode int, body any) error {
+	mediaType, encode := negotiate(r.accept)
+	if r.accept != "" {
+		r.w.Header().Add("Vary", "Accept")
+	}
+	if encode == nil {
+		body := &ResponseX{
+			Status:        0,
+			StatusNumber:  NotAcceptableStatusNumber,
+			StatusCode:    NotAcceptableStatusCode,
+			StatusMessage: r.localize(NotAcceptableStatusCode, NotAcceptableStatusMessage, r.vars),
+		}
+		r.w.Header().Set("Content-Type", "application/json")
+		r.w.WriteHeader(http.StatusNotAcceptable)
+		return json.NewEncoder(r.w).Encode(body)
 	}
 
+	r.w.Header().Set("Content-Type", mediaType)
+	if httpStatusCode != 0 {
+		r.w.WriteHeader(httpStatusCode)
+	}
+	return encode(r.w, body)
+}
+
+func (r *ResponseX) ReplyAs(res Response) error {
 	r.Status = res.GetStatus()
 	r.StatusNumber = res.GetStatusNumber()
 	r.StatusCode = res.GetStatusCode()
-	r.StatusMessage = res.GetStatusMessage()
+	r.StatusMessage = r.localize(r.StatusCode, res.GetStatusMessage(), r.vars)
 	if res.GetData() != nil {
 		r.Data = res.GetData()
 	}
 
-	return json.NewEncoder(r.w).Encode(r)
+	return r.reply(res.HttpStatusCode())
 }
 
 // Reply 'data' arguments only used on index 0 */
+// Message is replaced by message of code from DefaultBundle in negotiated language when available.
 func (r *ResponseX) Reply(status int, number string, code string, message string, data ...any) error {
-	r.w.Header().Set("Content-Type", "application/json")
-
 	r.Status = status
 	r.StatusNumber = number
 	r.StatusCode = code
-	r.StatusMessage = message
+	r.StatusMessage = r.localize(code, message, r.vars)
 	if len(data) > 0 {
 		r.Data = data[0]
 	}
 
-	return json.NewEncoder(r.w).Encode(r)
+	return r.reply(0)
 }
 
 // ReplyFailed 'data' arguments only used on index 0 */
@@ -134,7 +210,74 @@ func (r *ResponseX) ReplySuccess(number string, code string, message string, dat
 }
 
 func (r *ResponseX) ReplyCustom(httpStatusCode int, res any) error {
-	r.w.Header().Set("Content-Type", "application/json")
-	r.w.WriteHeader(httpStatusCode)
-	return json.NewEncoder(r.w).Encode(res)
+	return r.send(httpStatusCode, res)
+}
+
+// ReplyValidation reply 422 failed envelope with field errors from ParseTo, ParseOf or Validate on 'errors'.
+// Error that is not ValidationErrors is replied by ReplyError, so its message is never exposed.
+// 'data' arguments only used on index 0 */
+func (r *ResponseX) ReplyValidation(err error, data ...any) error {
+	var verr ValidationErrors
+	if err != nil && !errors.As(err, &verr) {
+		return r.ReplyError(err)
+	}
+
+	r.Status = 0
+	r.StatusNumber = ValidationStatusNumber
+	r.StatusCode = ValidationStatusCode
+	r.StatusMessage = r.localize(ValidationStatusCode, ValidationStatusMessage, r.vars)
+	if len(data) > 0 {
+		r.Data = data[0]
+	}
+
+	r.Errors = verr
+	if verr == nil {
+		r.Errors = ValidationErrors{}
+	}
+
+	return r.reply(http.StatusUnprocessableEntity)
+}
+
+// ReplyStatus reply envelope of catalog status with HTTP status code of the status, see Catalog.
+// 'data' arguments only used on index 0 */
+func (r *ResponseX) ReplyStatus(status Status, data ...any) error {
+	r.Status = status.status()
+	r.StatusNumber = status.Number
+	r.StatusCode = status.Code
+	r.StatusMessage = r.localize(status.Code, status.Message, status.With(r.vars).Vars())
+	if len(data) > 0 {
+		r.Data = data[0]
+	}
+
+	return r.reply(status.httpStatusCode())
+}
+
+// ReplyError reply failed envelope of Error found in err chain, or of registered mapper, see RegisterErrorMapper.
+// ValidationErrors is replied by ReplyValidation, any other error is replied as ErrInternal without its message.
+func (r *ResponseX) ReplyError(err error) error {
+	var verr ValidationErrors
+	if errors.As(err, &verr) {
+		return r.ReplyValidation(verr)
+	}
+	e, ok := toError(err)
+	if !ok {
+		if UnhandledError != nil && err != nil {
+			UnhandledError(err)
+		}
+		e = ErrInternal
+	}
+
+	r.Status = 0
+	r.StatusNumber = e.Number
+	r.StatusCode = e.Code
+	r.StatusMessage = r.localize(e.Code, e.Message, e.With(r.vars).Vars)
+	if e.Data != nil {
+		r.Data = e.Data
+	}
+
+	httpStatusCode := e.HttpStatusCode
+	if httpStatusCode == 0 {
+		httpStatusCode = http.StatusInternalServerError
+	}
+	return r.reply(httpStatusCode)
 }
@@ -0,0 +1,350 @@
+package jumper
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config point S3Storage to AWS S3 or any S3-compatible server like MinIO.
+type S3Config struct {
+	Endpoint  string // e.g. "https://s3.ap-southeast-1.amazonaws.com" or "http://localhost:9000"
+	Region    string // default "us-east-1"
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool   // use endpoint/bucket/key instead of bucket.endpoint/key, needed by most S3-compatible servers
+	PublicURL string // prefix used by URL instead of object endpoint, e.g. CDN address
+	Client    *http.Client
+}
+
+// S3Storage store objects into S3 bucket using signature version 4, request body is streamed unsigned.
+type S3Storage struct {
+	cfg S3Config
+}
+
+func NewS3Storage(cfg S3Config) *S3Storage {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &S3Storage{cfg: cfg}
+}
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3EmptyPayload    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// s3PartSize is buffered part of upload with unknown size, S3 require every part except the last
+	// to be at least 5 MB.
+	s3PartSize = 8 << 20
+)
+
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.PathStyle {
+		u.Path = "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = s3Escape(u.Path)
+	return u, nil
+}
+
+// s3Escape encode path the way signature version 4 expect, every byte except unreserved character and slash.
+func s3Escape(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || strings.IndexByte("-_.~/", c) != -1 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Query encode query the way signature version 4 expect, sorted by key and space as %20.
+func s3Query(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// s3ErrorCode return Code of S3 error document, empty when msg is not one.
+func s3ErrorCode(msg []byte) string {
+	var doc struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+	}
+	if xml.Unmarshal(msg, &doc) != nil || doc.XMLName.Local != "Error" {
+		return ""
+	}
+	return doc.Code
+}
+
+func (s *S3Storage) do(ctx context.Context, method string, key string, query url.Values, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = s3Query(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	payload := s3EmptyPayload
+	if body != nil {
+		payload = s3UnsignedPayload
+		req.ContentLength = size
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+	}
+	s.sign(req, payload, time.Now().UTC())
+
+	res, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode/100 != 2 {
+		defer res.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		// HEAD response has no body to tell missing key from missing bucket.
+		code := s3ErrorCode(msg)
+		if res.StatusCode == http.StatusNotFound && (code == "NoSuchKey" || code == "" && method == http.MethodHead) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("s3: %s %s: %s %s", method, key, res.Status, strings.TrimSpace(string(msg)))
+	}
+	return res, nil
+}
+
+// sign add signature version 4 Authorization header to req.
+func (s *S3Storage) sign(req *http.Request, payload string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		lower := strings.ToLower(k)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(k))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payload,
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	for _, part := range []string{s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", s3Algorithm+" Credential="+s.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// Put upload r, unknown size is sent as multipart upload in parts of s3PartSize, so at most one part is held in memory.
+// Unknown size smaller than one part is sent with single request.
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		part := make([]byte, s3PartSize)
+		n, err := io.ReadFull(r, part)
+		if err == nil {
+			return s.putMultipart(ctx, key, part, r, contentType)
+		} else if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		r, size = bytes.NewReader(part[:n]), int64(n)
+	}
+	body := io.NopCloser(r)
+	if size == 0 {
+		body = http.NoBody
+	}
+	res, err := s.do(ctx, http.MethodPut, key, nil, body, size, contentType)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+type s3CompleteUpload struct {
+	XMLName xml.Name `xml:"CompleteMultipartUpload"`
+	Parts   []s3Part `xml:"Part"`
+}
+
+type s3Part struct {
+	PartNumber int
+	ETag       string
+}
+
+// putMultipart upload part and the rest of r as multipart upload, upload is aborted when any step fail.
+func (s *S3Storage) putMultipart(ctx context.Context, key string, part []byte, r io.Reader, contentType string) error {
+	res, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, http.NoBody, 0, contentType)
+	if err != nil {
+		return err
+	}
+	var initiate struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(res.Body).Decode(&initiate)
+	res.Body.Close()
+	if err != nil {
+		return fmt.Errorf("s3: initiate upload %s: %w", key, err)
+	} else if initiate.UploadID == "" {
+		return fmt.Errorf("s3: initiate upload %s: missing upload id", key)
+	}
+
+	upload := url.Values{"uploadId": {initiate.UploadID}}
+	if err = s.uploadParts(ctx, key, upload, part, r); err != nil {
+		if res, abortErr := s.do(ctx, http.MethodDelete, key, upload, nil, 0, ""); abortErr == nil {
+			res.Body.Close()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *S3Storage) uploadParts(ctx context.Context, key string, upload url.Values, part []byte, r io.Reader) error {
+	var complete s3CompleteUpload
+	for number := 1; ; number++ {
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": upload["uploadId"]}
+		res, err := s.do(ctx, http.MethodPut, key, query, bytes.NewReader(part), int64(len(part)), "")
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		complete.Parts = append(complete.Parts, s3Part{PartNumber: number, ETag: res.Header.Get("ETag")})
+
+		n, err := io.ReadFull(r, part)
+		if err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			part = part[:n]
+		} else if err != nil {
+			return err
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(ctx, http.MethodPost, key, upload, bytes.NewReader(body), int64(len(body)), "application/xml")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// S3 may report failed completion with 200 status and error document, preceded by whitespace.
+	msg, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if code := s3ErrorCode(msg); code != "" {
+		return fmt.Errorf("s3: complete upload %s: %s", key, code)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Delete remove object, S3 answer missing key with success so it is checked by Stat first to fail with
+// ErrObjectNotFound like other storages.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+	res, err := s.do(ctx, http.MethodDelete, key, nil, nil, 0, "")
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	res, err := s.do(ctx, http.MethodHead, key, nil, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	info := &ObjectInfo{
+		ContentType: res.Header.Get("Content-Type"),
+		ETag:        strings.Trim(res.Header.Get("ETag"), `"`),
+	}
+	info.Key, _ = cleanKey(key)
+	info.Size, _ = strconv.ParseInt(res.Header.Get("Content-Length"), 10, 64)
+	info.ModTime, _ = http.ParseTime(res.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	key, _ = cleanKey(key)
+	if s.cfg.PublicURL != "" {
+		return joinURL(s.cfg.PublicURL, key)
+	}
+	u, err := s.objectURL(key)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
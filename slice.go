@@ -0,0 +1,96 @@
+package jumper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// items return key value as list. Array is used as is, string is split by comma and other value become single item.
+func (r *Request) items(key string) ([]interface{}, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return nil, err
+	}
+	switch v := param.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return []interface{}{}, nil
+		}
+		parts := strings.Split(v, ",")
+		list := make([]interface{}, len(parts))
+		for i, part := range parts {
+			list[i] = strings.TrimSpace(part)
+		}
+		return list, nil
+	}
+	return []interface{}{param}, nil
+}
+
+// sliceOf convert every item of key, error is ParamErrors keyed by item path like "id[2]".
+func sliceOf[T any](r *Request, key string, convert func(string, interface{}) (T, error)) ([]T, error) {
+	items, err := r.items(key)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]T, 0, len(items))
+	var errs ParamErrors
+	for i, item := range items {
+		v, err := convert(key+"["+strconv.Itoa(i)+"]", item)
+		if err != nil {
+			var pe *ParamError
+			if !errors.As(err, &pe) {
+				pe = &ParamError{Key: key, Value: item, Err: err}
+			}
+			errs = append(errs, pe)
+			continue
+		}
+		list = append(list, v)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return list, nil
+}
+
+// GetStringSlice get key value as []string, repeated key, id[] key, comma separated value and array are accepted.
+// Missing key or bad item give nil, use GetStringSliceE to get the error.
+func (r *Request) GetStringSlice(key string) []string {
+	list, _ := r.GetStringSliceE(key)
+	return list
+}
+
+// GetStringSliceE get key value as []string, see GetInt64Slice.
+// Only strict request can fail on item, object or array item is refused.
+func (r *Request) GetStringSliceE(key string) ([]string, error) {
+	return sliceOf(r, key, r.toString)
+}
+
+// GetInt64Slice get key value as []int64, see GetStringSlice for accepted values.
+// Error is ParamErrors of every bad item or *ParamError when key is missing.
+func (r *Request) GetInt64Slice(key string) ([]int64, error) {
+	return sliceOf(r, key, r.toInt64)
+}
+
+// GetUint64Slice get key value as []uint64, see GetInt64Slice.
+func (r *Request) GetUint64Slice(key string) ([]uint64, error) {
+	return sliceOf(r, key, r.toUint64)
+}
+
+// GetFloat64Slice get key value as []float64, see GetInt64Slice.
+func (r *Request) GetFloat64Slice(key string) ([]float64, error) {
+	return sliceOf(r, key, r.toFloat64)
+}
+
+// GetBoolSlice get key value as []bool, see GetInt64Slice.
+func (r *Request) GetBoolSlice(key string) ([]bool, error) {
+	return sliceOf(r, key, r.toBool)
+}
+
+// GetTimeSlice get key value as []time.Time, see GetInt64Slice and GetTimeE.
+func (r *Request) GetTimeSlice(key string) ([]time.Time, error) {
+	return sliceOf(r, key, r.toTime)
+}
@@ -0,0 +1,62 @@
+package jumper
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSliceSources(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?rep=1&rep=2&br[]=3&br[]=4&csv=5,%206&one=7&blank=", nil)
+	req, err := ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string][]int64{
+		"rep":   {1, 2},
+		"br":    {3, 4},
+		"csv":   {5, 6},
+		"one":   {7},
+		"blank": {},
+	} {
+		if got, err := req.GetInt64Slice(key); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetInt64Slice(%s) = %v, %v, want %v", key, got, err, want)
+		}
+	}
+	if got := req.GetStringSlice("csv"); !reflect.DeepEqual(got, []string{"5", "6"}) {
+		t.Errorf("GetStringSlice(csv) = %q", got)
+	}
+	if got, err := req.GetStringSliceE("absent"); got != nil || !errors.Is(err, ErrParamMissing) {
+		t.Errorf("GetStringSliceE(absent) = %q, %v, want ErrParamMissing", got, err)
+	}
+}
+
+func TestSliceItemErrors(t *testing.T) {
+	req, err := parseBody(t, "application/json", []byte(`{"ids":[1,"x",3,1.5],"flags":[true,"0"],"tags":["a",2,{"b":1}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = req.GetInt64Slice("ids")
+	var bad ParamErrors
+	if !errors.As(err, &bad) || len(bad) != 1 || bad[0].Key != "ids[1]" || !errors.Is(bad[0], ErrParamSyntax) {
+		t.Errorf("GetInt64Slice(ids) = %v, want ErrParamSyntax on ids[1]", err)
+	}
+	if got, err := req.GetBoolSlice("flags"); err != nil || !reflect.DeepEqual(got, []bool{true, false}) {
+		t.Errorf("GetBoolSlice(flags) = %v, %v", got, err)
+	}
+	if got, err := req.GetStringSliceE("tags"); err != nil || len(got) != 3 || got[1] != "2" {
+		t.Errorf("GetStringSliceE(tags) = %q, %v", got, err)
+	}
+
+	req.SetStrict(true)
+	if _, err = req.GetInt64Slice("ids"); !errors.As(err, &bad) || len(bad) != 2 || bad[1].Key != "ids[3]" || !errors.Is(bad[1], ErrParamType) {
+		t.Errorf("strict GetInt64Slice(ids) = %v, want errors on ids[1] and ids[3]", err)
+	}
+	if _, err = req.GetStringSliceE("tags"); !errors.As(err, &bad) || len(bad) != 1 || bad[0].Key != "tags[2]" || !errors.Is(bad[0], ErrParamType) {
+		t.Errorf("strict GetStringSliceE(tags) = %v, want ErrParamType on tags[2]", err)
+	}
+	if got := req.GetStringSlice("tags"); got != nil {
+		t.Errorf("strict GetStringSlice(tags) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,256 @@
+package jumper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Vars fill {name} placeholders of status message.
+type Vars map[string]any
+
+// Status is single entry of status catalog, declared once and replied with ReplyStatus.
+// Message may contain {name} placeholders filled from With, HttpStatusCode 0 means 200.
+type Status struct {
+	HttpStatusCode int    `json:"http_status_code" yaml:"http_status_code"`
+	Success        bool   `json:"success" yaml:"success"`
+	Number         string `json:"number" yaml:"number"`
+	Code           string `json:"code" yaml:"code"`
+	Message        string `json:"message" yaml:"message"`
+	Name           string `json:"name,omitempty" yaml:"name,omitempty"`               // Go identifier used by jumper-status, derived from Code when empty
+	Description    string `json:"description,omitempty" yaml:"description,omitempty"` // documentation only
+	vars           Vars
+}
+
+// With return copy of s whose message placeholders are filled from vars.
+func (s Status) With(vars Vars) Status {
+	merged := make(Vars, len(s.vars)+len(vars))
+	for k, v := range s.vars {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	s.vars = merged
+	return s
+}
+
+// Vars return placeholder values given by With.
+func (s Status) Vars() Vars {
+	return s.vars
+}
+
+var placeholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// Text return message with placeholders filled, unknown placeholder is kept as is.
+func (s Status) Text() string {
+	return fillPlaceholders(s.Message, s.vars)
+}
+
+func fillPlaceholders(message string, vars Vars) string {
+	if len(vars) == 0 {
+		return message
+	}
+	return placeholderRegex.ReplaceAllStringFunc(message, func(m string) string {
+		if v, ok := vars[m[1:len(m)-1]]; ok {
+			return fmt.Sprint(v)
+		}
+		return m
+	})
+}
+
+func (s Status) status() int {
+	if s.Success {
+		return 1
+	}
+	return 0
+}
+
+func (s Status) httpStatusCode() int {
+	if s.HttpStatusCode == 0 {
+		return http.StatusOK
+	}
+	return s.HttpStatusCode
+}
+
+// ErrDuplicateStatus is returned when status number or code is already registered in catalog.
+var ErrDuplicateStatus = errors.New("duplicate status")
+
+// Catalog hold statuses unique by number and by code.
+type Catalog struct {
+	mu       sync.RWMutex
+	byNumber map[string]Status
+	byCode   map[string]Status
+}
+
+// DefaultCatalog is catalog used by RegisterStatus, MustRegisterStatus and LoadStatusFile.
+var DefaultCatalog = NewCatalog()
+
+func NewCatalog() *Catalog {
+	return &Catalog{byNumber: map[string]Status{}, byCode: map[string]Status{}}
+}
+
+// Register add statuses, nothing is added when any of them is invalid or duplicated.
+func (c *Catalog) Register(statuses ...Status) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	numbers, codes := map[string]string{}, map[string]string{}
+	var errs []error
+	for _, s := range statuses {
+		if s.Number == "" || s.Code == "" {
+			errs = append(errs, fmt.Errorf("status %q %q: number and code are required", s.Number, s.Code))
+			continue
+		}
+		if s.HttpStatusCode != 0 && (s.HttpStatusCode < 100 || s.HttpStatusCode > 599) {
+			errs = append(errs, fmt.Errorf("status %s: invalid http status code %d", s.Code, s.HttpStatusCode))
+		}
+		if prev, ok := c.byNumber[s.Number]; ok {
+			errs = append(errs, fmt.Errorf("%w: number %s of %s is used by %s", ErrDuplicateStatus, s.Number, s.Code, prev.Code))
+		} else if prev, ok := numbers[s.Number]; ok {
+			errs = append(errs, fmt.Errorf("%w: number %s of %s is used by %s", ErrDuplicateStatus, s.Number, s.Code, prev))
+		}
+		if prev, ok := c.byCode[s.Code]; ok {
+			errs = append(errs, fmt.Errorf("%w: code %s of %s is used by %s", ErrDuplicateStatus, s.Code, s.Number, prev.Number))
+		} else if prev, ok := codes[s.Code]; ok {
+			errs = append(errs, fmt.Errorf("%w: code %s of %s is used by %s", ErrDuplicateStatus, s.Code, s.Number, prev))
+		}
+		numbers[s.Number], codes[s.Code] = s.Code, s.Number
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, s := range statuses {
+		s.vars = nil
+		c.byNumber[s.Number] = s
+		c.byCode[s.Code] = s
+	}
+	return nil
+}
+
+// MustRegister add status and return it, panic on invalid or duplicated status so startup fail early.
+//
+//	var UserNotFound = catalog.MustRegister(jumper.Status{HttpStatusCode: 404, Number: "4040001", Code: "USER_NOT_FOUND", Message: "User {id} not found"})
+func (c *Catalog) MustRegister(s Status) Status {
+	if err := c.Register(s); err != nil {
+		panic("jumper: " + err.Error())
+	}
+	return s
+}
+
+// ByNumber find status by its number.
+func (c *Catalog) ByNumber(number string) (Status, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.byNumber[number]
+	return s, ok
+}
+
+// ByCode find status by its code.
+func (c *Catalog) ByCode(code string) (Status, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.byCode[code]
+	return s, ok
+}
+
+// MustGet find status by code and panic when it is not registered.
+func (c *Catalog) MustGet(code string) Status {
+	s, ok := c.ByCode(code)
+	if !ok {
+		panic("jumper: status " + code + " is not registered")
+	}
+	return s
+}
+
+// All return registered statuses ordered by number.
+func (c *Catalog) All() []Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	list := make([]Status, 0, len(c.byNumber))
+	for _, s := range c.byNumber {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Number < list[j].Number })
+	return list
+}
+
+// ParseStatuses decode catalog file content, either list of statuses or object with "statuses" list.
+// Format is "json" or "yaml".
+func ParseStatuses(data []byte, format string) ([]Status, error) {
+	var file struct {
+		Statuses []Status `json:"statuses" yaml:"statuses"`
+	}
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(trimmed, &file.Statuses)
+		} else {
+			err = json.Unmarshal(data, &file)
+		}
+	case "yaml", "yml":
+		var node yaml.Node
+		if err = yaml.Unmarshal(data, &node); err == nil && len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+			err = node.Content[0].Decode(&file.Statuses)
+		} else if err == nil {
+			err = yaml.Unmarshal(data, &file)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported status catalog format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("status catalog: %w", err)
+	}
+	return file.Statuses, nil
+}
+
+// Load register statuses read from r, see ParseStatuses.
+func (c *Catalog) Load(r io.Reader, format string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	statuses, err := ParseStatuses(data, format)
+	if err != nil {
+		return err
+	}
+	return c.Register(statuses...)
+}
+
+// LoadFile register statuses from .json, .yaml or .yml file.
+func (c *Catalog) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return c.Load(file, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// RegisterStatus add statuses into DefaultCatalog.
+func RegisterStatus(statuses ...Status) error {
+	return DefaultCatalog.Register(statuses...)
+}
+
+// MustRegisterStatus add status into DefaultCatalog and return it, panic on invalid or duplicated status.
+func MustRegisterStatus(s Status) Status {
+	return DefaultCatalog.MustRegister(s)
+}
+
+// LoadStatusFile register statuses from file into DefaultCatalog.
+func LoadStatusFile(path string) error {
+	return DefaultCatalog.LoadFile(path)
+}
@@ -0,0 +1,266 @@
+package jumper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrObjectNotFound is returned by Storage when key does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describe stored object.
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+	ETag        string
+}
+
+// Storage keep uploaded files by key, see LocalStorage, MemoryStorage and S3Storage.
+// Size given to Put is -1 when unknown. Get, Stat and Delete of missing key fail with ErrObjectNotFound.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	URL(key string) string
+}
+
+// StoreTo stream the upload into storage under key.
+func (f *File) StoreTo(ctx context.Context, storage Storage, key string) error {
+	size := int64(-1)
+	contentType := ""
+	if fh := f.GetFileHeader(); fh != nil {
+		size, contentType = fh.Size, fh.Header.Get("Content-Type")
+	}
+	if err := storage.Put(ctx, key, f.GetFile(), size, contentType); err != nil {
+		return err
+	}
+	f.name = key
+	return nil
+}
+
+// StoreTo stream the part into storage under key.
+func (p *Part) StoreTo(ctx context.Context, storage Storage, key string) error {
+	return storage.Put(ctx, key, p, -1, p.ContentType())
+}
+
+// cleanKey normalize key into slash separated path that cannot escape storage root.
+func cleanKey(key string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(key, `\`, "/")), "/")
+	if cleaned == "" {
+		return "", errors.New("empty storage key")
+	}
+	return cleaned, nil
+}
+
+// LocalStorage store objects as files under Root, BaseURL is prefixed to key by URL.
+type LocalStorage struct {
+	Root     string
+	BaseURL  string
+	DirPerm  os.FileMode
+	FilePerm os.FileMode
+}
+
+func NewLocalStorage(root string, baseURL string) *LocalStorage {
+	return &LocalStorage{Root: root, BaseURL: baseURL, DirPerm: 0755, FilePerm: 0644}
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+// Put write r into temporary file next to target and rename it, so reader never see partial object.
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(name), s.DirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, contextReader{ctx, r})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), s.FilePerm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return file, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(name); errors.Is(err, os.ErrNotExist) {
+		return ErrObjectNotFound
+	}
+	return err
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(name)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && fi.IsDir()) {
+		return nil, ErrObjectNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	key, _ = cleanKey(key)
+	return &ObjectInfo{
+		Key:         key,
+		Size:        fi.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(name)),
+		ModTime:     fi.ModTime(),
+	}, nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	key, _ = cleanKey(key)
+	return joinURL(s.BaseURL, key)
+}
+
+func joinURL(base string, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(segments, "/")
+}
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+// MemoryStorage keep objects in memory, meant for tests.
+type MemoryStorage struct {
+	BaseURL string
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{BaseURL: "memory://", objects: map[string]memoryObject{}}
+}
+
+func (s *MemoryStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(contextReader{ctx, r})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{data: data, contentType: contentType, modTime: time.Now()}
+	return nil
+}
+
+func (s *MemoryStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, _ = cleanKey(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	key, _ = cleanKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return ErrObjectNotFound
+	}
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *MemoryStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	key, _ = cleanKey(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return &ObjectInfo{Key: key, Size: int64(len(obj.data)), ContentType: obj.contentType, ModTime: obj.modTime}, nil
+}
+
+func (s *MemoryStorage) URL(key string) string {
+	key, _ = cleanKey(key)
+	return joinURL(s.BaseURL, key)
+}
+
+// Keys return stored keys in order.
+func (s *MemoryStorage) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contextReader stop reading once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(b []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(b)
+}
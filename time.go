@@ -0,0 +1,251 @@
+package jumper
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is layout of date-only value accepted by GetTime and GetDate.
+const DateLayout = "2006-01-02"
+
+// TimeLayouts are tried in order by GetTime and binder, use WithTimeLayouts or SetTimeLayouts to override per request.
+var TimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05.000Z07:00", // RFC3339Mili
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	DateLayout,
+}
+
+// DefaultLocation is applied to value without zone, use WithLocation or SetLocation to override per request.
+var DefaultLocation = time.UTC
+
+// unixMilliThreshold split Unix seconds from milliseconds, 1e11 seconds is far after year 5000.
+const unixMilliThreshold = 1e11
+
+// SetTimeLayouts replace layouts tried by GetTime on this request, no layout restore TimeLayouts.
+func (r *Request) SetTimeLayouts(layouts ...string) *Request {
+	r.timeLayouts = layouts
+	return r
+}
+
+// SetLocation set location applied to value without zone on this request, see DefaultLocation.
+func (r *Request) SetLocation(loc *time.Location) *Request {
+	r.location = loc
+	return r
+}
+
+func (r *Request) layouts() []string {
+	if r.timeLayouts != nil {
+		return r.timeLayouts
+	}
+	return TimeLayouts
+}
+
+func (r *Request) loc() *time.Location {
+	if r.location != nil {
+		return r.location
+	}
+	if DefaultLocation != nil {
+		return DefaultLocation
+	}
+	return time.UTC
+}
+
+// parseTime parse value with request layouts, numeric value is Unix seconds or milliseconds.
+func (r *Request) parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range r.layouts() {
+		if t, err := time.ParseInLocation(layout, value, r.loc()); err == nil {
+			return t, nil
+		}
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if i >= unixMilliThreshold || i <= -unixMilliThreshold {
+			return time.UnixMilli(i).In(r.loc()), nil
+		}
+		return time.Unix(i, 0).In(r.loc()), nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return r.unixTime(f)
+	}
+	return time.Time{}, ErrParamSyntax
+}
+
+func (r *Request) unixTime(f float64) (time.Time, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, ErrParamSyntax
+	}
+	if math.Abs(f) >= unixMilliThreshold {
+		f /= 1000
+	}
+	if math.Abs(f) > float64(math.MaxInt64/int64(time.Second)) {
+		return time.Time{}, ErrParamOverflow
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).In(r.loc()), nil
+}
+
+// GetTimeE get key value as time.Time, error is *ParamError.
+// String is parsed with request layouts, number or numeric string is Unix seconds, or milliseconds when above 1e11.
+func (r *Request) GetTimeE(key string) (time.Time, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.toTime(key, param)
+}
+
+func (r *Request) toTime(key string, param interface{}) (t time.Time, err error) {
+	switch v := param.(type) {
+	case string:
+		t, err = r.parseTime(v)
+	case json.Number:
+		t, err = r.parseTime(v.String())
+	case float64:
+		t, err = r.unixTime(v)
+	case int:
+		t, err = r.unixTime(float64(v))
+	default:
+		err = ErrParamType
+	}
+	if err != nil {
+		return time.Time{}, r.paramError(key, param, err)
+	}
+	return t, nil
+}
+
+// GetDate get key value as date at midnight, time part of datetime value is dropped keeping its own zone.
+func (r *Request) GetDate(key string) (*time.Time, error) {
+	t, err := r.GetTimeE(key)
+	if err != nil {
+		return nil, err
+	}
+	y, m, d := t.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return &date, nil
+}
+
+// GetDuration get key value as time.Duration, error is *ParamError.
+// String is Go duration like "1h30m" or ISO-8601 duration like "P1DT2H", number is seconds.
+func (r *Request) GetDuration(key string) (time.Duration, error) {
+	param, err := r.present(key)
+	if err != nil {
+		return 0, err
+	}
+	var d time.Duration
+	switch v := param.(type) {
+	case string:
+		d, err = parseDuration(v)
+	case json.Number:
+		d, err = parseDuration(v.String())
+	case float64:
+		d, err = secondsDuration(v)
+	case int:
+		d, err = secondsDuration(float64(v))
+	default:
+		err = ErrParamType
+	}
+	if err != nil {
+		return 0, r.paramError(key, param, err)
+	}
+	return d, nil
+}
+
+// GetTimeRange get both keys as time, to must not be before from.
+func (r *Request) GetTimeRange(fromKey, toKey string) (from, to time.Time, err error) {
+	if from, err = r.GetTimeE(fromKey); err != nil {
+		return
+	}
+	if to, err = r.GetTimeE(toKey); err != nil {
+		return
+	}
+	if to.Before(from) {
+		param, _ := r.lookup(toKey)
+		err = r.paramError(toKey, param, ErrParamOverflow)
+	}
+	return
+}
+
+func secondsDuration(f float64) (time.Duration, error) {
+	if math.IsNaN(f) || math.Abs(f*float64(time.Second)) >= math.MaxInt64 {
+		return 0, ErrParamOverflow
+	}
+	return time.Duration(math.Round(f * float64(time.Second))), nil
+}
+
+// parseDuration accept Go duration, ISO-8601 duration or plain seconds.
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return secondsDuration(f)
+	}
+	return parseISODuration(value)
+}
+
+var isoUnits = map[byte]time.Duration{
+	'W': 7 * 24 * time.Hour,
+	'D': 24 * time.Hour,
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+}
+
+// parseISODuration parse ISO-8601 duration of weeks, days and time, e.g. "P1W", "P1DT2H30M" or "-PT0.5S".
+// Years and months have no fixed length, so they are refused.
+func parseISODuration(value string) (time.Duration, error) {
+	s := strings.ToUpper(value)
+	sign := 1.0
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, ErrParamSyntax
+	}
+
+	var total float64
+	inTime := false
+	number := ""
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 'T' && !inTime && number == "":
+			inTime = true
+		case (c >= '0' && c <= '9') || c == '.' || c == ',':
+			if c == ',' {
+				c = '.'
+			}
+			number += string(c)
+		default:
+			unit, ok := isoUnits[c]
+			if !ok || number == "" || (inTime && (c == 'W' || c == 'D')) || (!inTime && (c == 'H' || c == 'S')) {
+				return 0, ErrParamSyntax
+			}
+			if c == 'M' && !inTime {
+				return 0, ErrParamSyntax
+			}
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, ErrParamSyntax
+			}
+			total += f * float64(unit)
+			number = ""
+		}
+	}
+	if number != "" || strings.HasSuffix(s, "T") {
+		return 0, ErrParamSyntax
+	}
+	if total >= math.MaxInt64 {
+		return 0, ErrParamOverflow
+	}
+	return time.Duration(sign * total), nil
+}
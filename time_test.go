@@ -0,0 +1,131 @@
+package jumper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetTime(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	req, err := parseBody(t, "application/json", []byte(`{
+		"rfc": "2024-03-01T10:20:30+07:00",
+		"milli": "2024-03-01T10:20:30.123Z",
+		"local": "2024-03-01 10:20:30",
+		"date": "2024-03-01",
+		"unix": 1709263230,
+		"unixms": 1709263230123,
+		"unixstr": "1709263230",
+		"frac": 1709263230.5,
+		"bad": "yesterday",
+		"obj": {"a": 1}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetLocation(jakarta)
+
+	unix := time.Unix(1709263230, 0)
+	for key, want := range map[string]time.Time{
+		"rfc":     time.Date(2024, 3, 1, 10, 20, 30, 0, jakarta),
+		"milli":   time.Date(2024, 3, 1, 10, 20, 30, 123e6, time.UTC),
+		"local":   time.Date(2024, 3, 1, 10, 20, 30, 0, jakarta),
+		"date":    time.Date(2024, 3, 1, 0, 0, 0, 0, jakarta),
+		"unix":    unix,
+		"unixms":  unix.Add(123 * time.Millisecond),
+		"unixstr": unix,
+		"frac":    unix.Add(500 * time.Millisecond),
+	} {
+		got, err := req.GetTimeE(key)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("GetTimeE(%s) = %v, %v, want %v", key, got, err, want)
+		}
+	}
+	if got, _ := req.GetTimeE("local"); got.Location() != jakarta {
+		t.Errorf("GetTimeE(local) location = %v, want request location", got.Location())
+	}
+
+	if _, err := req.GetTimeE("bad"); !errors.Is(err, ErrParamSyntax) {
+		t.Errorf("GetTimeE(bad) = %v, want ErrParamSyntax", err)
+	}
+	if _, err := req.GetTimeE("obj"); !errors.Is(err, ErrParamType) {
+		t.Errorf("GetTimeE(obj) = %v, want ErrParamType", err)
+	}
+	if got, err := req.GetTime("absent"); got != nil || !errors.Is(err, ErrParamMissing) {
+		t.Errorf("GetTime(absent) = %v, %v, want nil ErrParamMissing", got, err)
+	}
+
+	req.SetTimeLayouts("02/01/2006")
+	if _, err := req.GetTimeE("date"); !errors.Is(err, ErrParamSyntax) {
+		t.Errorf("GetTimeE(date) with custom layouts = %v, want ErrParamSyntax", err)
+	}
+	req.SetTimeLayouts()
+	if _, err := req.GetTimeE("date"); err != nil {
+		t.Errorf("GetTimeE(date) after restoring layouts = %v", err)
+	}
+}
+
+func TestGetDateAndRange(t *testing.T) {
+	req, err := parseBody(t, "application/json", []byte(`{"at":"2024-03-01T23:30:00+07:00","from":"2024-03-02","to":"2024-03-01"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	date, err := req.GetDate("at")
+	if err != nil || date.Format(time.RFC3339) != "2024-03-01T00:00:00+07:00" {
+		t.Errorf("GetDate(at) = %v, %v, want midnight in own zone", date, err)
+	}
+	if _, _, err := req.GetTimeRange("to", "from"); err != nil {
+		t.Errorf("GetTimeRange(to, from) = %v", err)
+	}
+	var perr *ParamError
+	if _, _, err := req.GetTimeRange("from", "to"); !errors.As(err, &perr) || perr.Key != "to" || !errors.Is(err, ErrParamOverflow) {
+		t.Errorf("GetTimeRange(from, to) = %v, want ErrParamOverflow on to", err)
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	req, err := parseBody(t, "application/json", []byte(`{
+		"go": "1h30m",
+		"week": "P1W",
+		"iso": "P1DT2H30M",
+		"half": "-PT0.5S",
+		"comma": "PT1,5M",
+		"secs": 90,
+		"secstr": "2.5",
+		"year": "P1Y",
+		"month": "P1M",
+		"mixed": "PT1D",
+		"trail": "P1DT",
+		"huge": "P999999999W",
+		"list": [1]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]time.Duration{
+		"go":     90 * time.Minute,
+		"week":   7 * 24 * time.Hour,
+		"iso":    26*time.Hour + 30*time.Minute,
+		"half":   -500 * time.Millisecond,
+		"comma":  90 * time.Second,
+		"secs":   90 * time.Second,
+		"secstr": 2500 * time.Millisecond,
+	} {
+		if got, err := req.GetDuration(key); err != nil || got != want {
+			t.Errorf("GetDuration(%s) = %v, %v, want %v", key, got, err, want)
+		}
+	}
+	for key, want := range map[string]error{
+		"year":   ErrParamSyntax,
+		"month":  ErrParamSyntax,
+		"mixed":  ErrParamSyntax,
+		"trail":  ErrParamSyntax,
+		"huge":   ErrParamOverflow,
+		"list":   ErrParamType,
+		"absent": ErrParamMissing,
+	} {
+		if _, err := req.GetDuration(key); !errors.Is(err, want) {
+			t.Errorf("GetDuration(%s) = %v, want %v", key, err, want)
+		}
+	}
+}
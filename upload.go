@@ -0,0 +1,245 @@
+package jumper
+
+import (
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FileRule is `validate` rule applied to uploaded file by File.Check, see AllowMimeTypes and friends.
+type FileRule struct {
+	name  string
+	param string
+}
+
+func (r FileRule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// AllowMimeTypes accept file whose sniffed content type match one of types, wildcard like "image/*" is allowed.
+func AllowMimeTypes(types ...string) FileRule {
+	return FileRule{"mimetypes", strings.Join(types, " ")}
+}
+
+// AllowExts accept file whose name end with one of extensions, with or without leading dot.
+func AllowExts(exts ...string) FileRule {
+	return FileRule{"exts", strings.Join(exts, " ")}
+}
+
+// MinSize reject file smaller than n bytes.
+func MinSize(n int64) FileRule {
+	return FileRule{"min", strconv.FormatInt(n, 10)}
+}
+
+// MaxSize reject file bigger than n bytes.
+func MaxSize(n int64) FileRule {
+	return FileRule{"max", strconv.FormatInt(n, 10)}
+}
+
+// IsImage accept only file decodable as GIF, JPEG or PNG.
+func IsImage() FileRule {
+	return FileRule{"image", ""}
+}
+
+// MinDimensions reject image narrower than width or lower than height, 0 skip the side.
+func MinDimensions(width, height int) FileRule {
+	return FileRule{"mindimensions", strconv.Itoa(width) + "x" + strconv.Itoa(height)}
+}
+
+// MaxDimensions reject image wider than width or higher than height, 0 skip the side.
+func MaxDimensions(width, height int) FileRule {
+	return FileRule{"maxdimensions", strconv.Itoa(width) + "x" + strconv.Itoa(height)}
+}
+
+// Check validate file against rules, failures are returned as ValidationErrors under field.
+func (f *File) Check(field string, rules ...FileRule) error {
+	tags := make([]string, len(rules))
+	for i, rl := range rules {
+		tags[i] = rl.String()
+	}
+	var errs ValidationErrors
+	validateField(reflect.ValueOf(f), field, tags, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Size return size of uploaded file in bytes.
+func (f *File) Size() int64 {
+	if f.fh == nil {
+		return 0
+	}
+	return f.fh.Size
+}
+
+// Filename return name of the file given by client.
+func (f *File) Filename() string {
+	if f.fh == nil {
+		return ""
+	}
+	return f.fh.Filename
+}
+
+// Ext return lower case extension of client file name without leading dot.
+func (f *File) Ext() string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(f.Filename()), "."))
+}
+
+// ContentType detect content type from first bytes of the file instead of trusting client header.
+func (f *File) ContentType() (string, error) {
+	if f.contentType != "" {
+		return f.contentType, nil
+	}
+	head := make([]byte, 512)
+	err := f.peek(func(r io.Reader) error {
+		n, err := io.ReadFull(r, head)
+		head = head[:n]
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	f.contentType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	return f.contentType, nil
+}
+
+// ImageSize decode width and height of GIF, JPEG or PNG file without decoding whole image.
+func (f *File) ImageSize() (width, height int, err error) {
+	err = f.peek(func(r io.Reader) error {
+		cfg, _, err := image.DecodeConfig(r)
+		width, height = cfg.Width, cfg.Height
+		return err
+	})
+	return
+}
+
+// peek let fn read the file from start and rewind it afterward.
+func (f *File) peek(fn func(io.Reader) error) error {
+	if f.f == nil {
+		return errors.New("file is not opened")
+	}
+	if _, err := f.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	err := fn(f.f)
+	if _, serr := f.f.Seek(0, io.SeekStart); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// MarshalJSON write client file name, so File reported in FieldError is readable.
+func (f File) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(f.Filename())), nil
+}
+
+// fileOf return *File held by rule field, nil for other types.
+func fileOf(v reflect.Value) *File {
+	if v.Type() != fileType.Elem() {
+		return nil
+	}
+	if v.CanAddr() {
+		return v.Addr().Interface().(*File)
+	}
+	f := v.Interface().(File)
+	return &f
+}
+
+// parseSize parse byte size like "512", "100KB", "2MB" or "1GB", units are 1024 based.
+func parseSize(s string) (int64, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+func parseDimensions(param string) (width, height int, ok bool) {
+	w, h, found := strings.Cut(strings.ToLower(param), "x")
+	if !found {
+		return 0, 0, false
+	}
+	width, werr := strconv.Atoi(strings.TrimSpace(w))
+	height, herr := strconv.Atoi(strings.TrimSpace(h))
+	return width, height, werr == nil && herr == nil
+}
+
+func dimensionRule(fits func(size, limit int) bool) RuleFunc {
+	return func(v reflect.Value, p string) bool {
+		f := fileOf(v)
+		width, height, ok := parseDimensions(p)
+		if f == nil || !ok {
+			return false
+		}
+		w, h, err := f.ImageSize()
+		return err == nil && (width == 0 || fits(w, width)) && (height == 0 || fits(h, height))
+	}
+}
+
+func init() {
+	RegisterRule("mimetypes", func(v reflect.Value, p string) bool {
+		f := fileOf(v)
+		if f == nil {
+			return false
+		}
+		contentType, err := f.ContentType()
+		if err != nil {
+			return false
+		}
+		for _, allowed := range strings.Fields(strings.ToLower(p)) {
+			if matchMediaRange(allowed, contentType) {
+				return true
+			}
+		}
+		return false
+	}, "{field} must be a file of type [{param}]")
+	RegisterRule("exts", func(v reflect.Value, p string) bool {
+		f := fileOf(v)
+		if f == nil {
+			return false
+		}
+		for _, ext := range strings.Fields(strings.ToLower(p)) {
+			if strings.TrimPrefix(ext, ".") == f.Ext() {
+				return true
+			}
+		}
+		return false
+	}, "{field} must have extension [{param}]")
+	RegisterRule("image", func(v reflect.Value, _ string) bool {
+		f := fileOf(v)
+		if f == nil {
+			return false
+		}
+		_, _, err := f.ImageSize()
+		return err == nil
+	}, "{field} must be an image")
+	RegisterRule("mindimensions", dimensionRule(func(size, limit int) bool { return size >= limit }), "{field} must be at least {param} pixels")
+	RegisterRule("maxdimensions", dimensionRule(func(size, limit int) bool { return size <= limit }), "{field} must be at most {param} pixels")
+}
@@ -0,0 +1,114 @@
+package jumper
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+)
+
+func uploadFile(t *testing.T, name string, content []byte) *File {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fw, _ := w.CreateFormFile("file", name)
+	fw.Write(content)
+	w.Close()
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	req, err := ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := req.GetFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func pngOf(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func failedRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Check() = %v, want ValidationErrors", err)
+	}
+	rules := map[string]string{}
+	for _, e := range errs {
+		rules[e.Rule] = e.Field
+	}
+	return rules
+}
+
+func TestFileCheckSniffsContent(t *testing.T) {
+	exe := append([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff"), make([]byte, 64)...)
+	f := uploadFile(t, "avatar.png", exe)
+	rules := failedRules(t, f.Check("avatar", AllowMimeTypes("image/png", "image/jpeg"), AllowExts("png"), IsImage()))
+	if len(rules) != 2 || rules["mimetypes"] != "avatar" || rules["image"] != "avatar" {
+		t.Errorf("Check(executable named .png) failed rules = %v, want mimetypes and image", rules)
+	}
+
+	f = uploadFile(t, "avatar.PNG", pngOf(t, 40, 30))
+	if contentType, err := f.ContentType(); err != nil || contentType != "image/png" {
+		t.Errorf("ContentType() = %q, %v, want image/png", contentType, err)
+	}
+	err := f.Check("avatar", AllowMimeTypes("image/*"), AllowExts(".png"), IsImage(), MinDimensions(40, 0), MaxDimensions(100, 30))
+	if err != nil {
+		t.Errorf("Check(png) = %v", err)
+	}
+	if rules = failedRules(t, f.Check("avatar", MinDimensions(0, 31), MaxDimensions(39, 0))); len(rules) != 2 {
+		t.Errorf("Check(dimensions) failed rules = %v, want mindimensions and maxdimensions", rules)
+	}
+}
+
+func TestFileCheckSize(t *testing.T) {
+	f := uploadFile(t, "notes.txt", bytes.Repeat([]byte("a"), 2048))
+	if err := f.Check("notes", MinSize(2048), MaxSize(2048)); err != nil {
+		t.Errorf("Check(exact size) = %v", err)
+	}
+	if rules := failedRules(t, f.Check("notes", MaxSize(2047))); rules["max"] != "notes" {
+		t.Errorf("Check(MaxSize) failed rules = %v, want max", rules)
+	}
+	if rules := failedRules(t, f.Check("notes", MinSize(4096))); rules["min"] != "notes" {
+		t.Errorf("Check(MinSize) failed rules = %v, want min", rules)
+	}
+
+	type form struct {
+		Notes *File `json:"notes" validate:"required,max=1KB"`
+	}
+	if rules := failedRules(t, Validate(form{Notes: f})); rules["max"] != "notes" {
+		t.Errorf("Validate(max=1KB) failed rules = %v, want max", rules)
+	}
+}
+
+func TestUploadCountLimit(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		fw, _ := w.CreateFormFile("docs", name)
+		fw.Write([]byte(name))
+	}
+	w.Close()
+	r := httptest.NewRequest("POST", "/", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	rec := httptest.NewRecorder()
+	req := PlugRequestWith(r, rec, WithMaxFiles(2))
+	if !req.Replied() || !errors.Is(req.Err(), ErrBodyTooLarge) || rec.Code != 413 {
+		t.Errorf("PlugRequestWith(3 files, max 2) = %d, %v, want 413 ErrBodyTooLarge", rec.Code, req.Err())
+	}
+}
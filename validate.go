@@ -0,0 +1,582 @@
+package jumper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError describe single failed rule of `validate` tag.
+// Value is the rejected input, it is only written into reply when ExposeFieldValues is true.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Value   any    `json:"-"`
+	Message string `json:"message"`
+}
+
+// ExposeFieldValues write FieldError.Value as "value" in validation reply, off so secrets like password are not echoed.
+var ExposeFieldValues = false
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	type fieldError FieldError
+	if !ExposeFieldValues || e.Value == nil {
+		return json.Marshal((*fieldError)(e))
+	}
+	return json.Marshal(struct {
+		*fieldError
+		Value any `json:"value"`
+	}{(*fieldError)(e), e.Value})
+}
+
+// ValidationErrors is returned by ParseTo, ParseOf and Validate when payload doesn't satisfy `validate` tags.
+type ValidationErrors []*FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields group error messages by field path.
+func (v ValidationErrors) Fields() map[string][]string {
+	fields := map[string][]string{}
+	for _, e := range v {
+		fields[e.Field] = append(fields[e.Field], e.Message)
+	}
+	return fields
+}
+
+// RuleFunc report whether field satisfy the rule, param is the value after '=' in tag.
+type RuleFunc func(field reflect.Value, param string) bool
+
+type rule struct {
+	check   RuleFunc
+	message string
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]rule{}
+)
+
+// RegisterRule add custom rule to `validate` tag language.
+// Message may contain {field} and {param} placeholders.
+func RegisterRule(name string, fn RuleFunc, message string) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = rule{check: fn, message: message}
+	tagChecks.Range(func(k, _ any) bool {
+		tagChecks.Delete(k)
+		return true
+	})
+}
+
+func lookupRule(name string) (rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	rl, ok := rules[name]
+	return rl, ok
+}
+
+var (
+	uuidRegex       = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	timeType        = reflect.TypeOf(time.Time{})
+	decimalType     = reflect.TypeOf(Decimal{})
+	exactNumberType = reflect.TypeOf(ExactNumber(""))
+)
+
+func init() {
+	RegisterRule("min", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c >= 0 }, "{field} must be at least {param}")
+	RegisterRule("max", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c <= 0 }, "{field} must be at most {param}")
+	RegisterRule("len", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c == 0 }, "{field} length must be {param}")
+	RegisterRule("eq", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c == 0 }, "{field} must be equal to {param}")
+	RegisterRule("ne", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c != 0 }, "{field} must not be equal to {param}")
+	RegisterRule("gt", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c > 0 }, "{field} must be greater than {param}")
+	RegisterRule("gte", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c >= 0 }, "{field} must be greater than or equal to {param}")
+	RegisterRule("lt", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c < 0 }, "{field} must be less than {param}")
+	RegisterRule("lte", func(v reflect.Value, p string) bool { c, ok := compare(v, p); return ok && c <= 0 }, "{field} must be less than or equal to {param}")
+	RegisterRule("oneof", func(v reflect.Value, p string) bool {
+		s := fmt.Sprint(v.Interface())
+		for _, o := range strings.Fields(p) {
+			if s == o {
+				return true
+			}
+		}
+		return false
+	}, "{field} must be one of [{param}]")
+	RegisterRule("email", func(v reflect.Value, _ string) bool {
+		s, ok := stringOf(v)
+		if !ok {
+			return false
+		}
+		a, err := mail.ParseAddress(s)
+		return err == nil && a.Address == s
+	}, "{field} must be a valid email address")
+	RegisterRule("url", func(v reflect.Value, _ string) bool {
+		s, ok := stringOf(v)
+		if !ok {
+			return false
+		}
+		u, err := url.ParseRequestURI(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	}, "{field} must be a valid URL")
+	RegisterRule("uuid", func(v reflect.Value, _ string) bool {
+		s, ok := stringOf(v)
+		return ok && uuidRegex.MatchString(s)
+	}, "{field} must be a valid UUID")
+	RegisterRule("alpha", stringRule(unicode.IsLetter), "{field} must contain only letters")
+	RegisterRule("alphanum", stringRule(func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }), "{field} must contain only letters and numbers")
+	RegisterRule("numeric", stringRule(unicode.IsDigit), "{field} must contain only numbers")
+	RegisterRule("contains", func(v reflect.Value, p string) bool {
+		s, ok := stringOf(v)
+		return ok && strings.Contains(s, p)
+	}, "{field} must contain '{param}'")
+	RegisterRule("startswith", func(v reflect.Value, p string) bool {
+		s, ok := stringOf(v)
+		return ok && strings.HasPrefix(s, p)
+	}, "{field} must start with '{param}'")
+	RegisterRule("endswith", func(v reflect.Value, p string) bool {
+		s, ok := stringOf(v)
+		return ok && strings.HasSuffix(s, p)
+	}, "{field} must end with '{param}'")
+}
+
+func stringRule(allowed func(rune) bool) RuleFunc {
+	return func(v reflect.Value, _ string) bool {
+		s, ok := stringOf(v)
+		if !ok || s == "" {
+			return false
+		}
+		for _, r := range s {
+			if !allowed(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func stringOf(v reflect.Value) (string, bool) {
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// compare measure v against param, strings and collections are measured by length, numbers by value
+// and File by size, where param may carry unit like "2MB". Decimal and ExactNumber are compared exactly.
+func compare(v reflect.Value, param string) (int, bool) {
+	switch v.Type() {
+	case decimalType:
+		return compareDecimal(v.Interface().(Decimal), param)
+	case exactNumberType:
+		d, err := v.Interface().(ExactNumber).Decimal()
+		if err != nil {
+			return 0, false
+		}
+		return compareDecimal(d, param)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return compareInt(int64(utf8.RuneCountInString(v.String())), param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return compareInt(int64(v.Len()), param)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(param)
+			if err != nil {
+				return 0, false
+			}
+			return compareInt(v.Int()-int64(d), "0")
+		}
+		return compareInt(v.Int(), param)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		p, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return compareFloat(float64(v.Uint()), param)
+		}
+		switch {
+		case v.Uint() < p:
+			return -1, true
+		case v.Uint() > p:
+			return 1, true
+		}
+		return 0, true
+	case reflect.Float32, reflect.Float64:
+		return compareFloat(v.Float(), param)
+	case reflect.Struct:
+		if f := fileOf(v); f != nil {
+			size, ok := parseSize(param)
+			if !ok {
+				return 0, false
+			}
+			return compareInt(f.Size(), strconv.FormatInt(size, 10))
+		}
+	}
+	return 0, false
+}
+
+func compareDecimal(d Decimal, param string) (int, bool) {
+	p, err := ParseDecimal(param)
+	if err != nil {
+		return 0, false
+	}
+	return d.Cmp(p), true
+}
+
+func compareInt(i int64, param string) (int, bool) {
+	p, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return compareFloat(float64(i), param)
+	}
+	switch {
+	case i < p:
+		return -1, true
+	case i > p:
+		return 1, true
+	}
+	return 0, true
+}
+
+func compareFloat(f float64, param string) (int, bool) {
+	p, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case f < p:
+		return -1, true
+	case f > p:
+		return 1, true
+	}
+	return 0, true
+}
+
+// ErrUnknownRule is returned when `validate` tag use rule that is not registered, see ValidateTags.
+var ErrUnknownRule = errors.New("unknown validation rule")
+
+type tagCheck struct {
+	err error
+}
+
+// tagChecks cache result of checkTags per type.
+var tagChecks sync.Map
+
+// ValidateTags report every `validate` tag of v's type using unregistered rule, wrapping ErrUnknownRule.
+// Result is cached per type, fields of interface type are not checked.
+func ValidateTags(v any) error {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil
+	}
+	return checkTags(t)
+}
+
+// MustValidateTags panic when ValidateTags fail, call it from init or test so typo is found before serving.
+//
+//	func init() { jumper.MustValidateTags(CreateUser{}) }
+func MustValidateTags(v any) {
+	if err := ValidateTags(v); err != nil {
+		panic("jumper: " + err.Error())
+	}
+}
+
+func checkTags(t reflect.Type) error {
+	if c, ok := tagChecks.Load(t); ok {
+		return c.(tagCheck).err
+	}
+	var errs []error
+	walkTags(t, map[reflect.Type]bool{}, &errs)
+	err := errors.Join(errs...)
+	tagChecks.Store(t, tagCheck{err})
+	return err
+}
+
+func walkTags(t reflect.Type, seen map[reflect.Type]bool, errs *[]error) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			for _, tag := range strings.Split(tag, ",") {
+				name, _, _ := strings.Cut(strings.TrimSpace(tag), "=")
+				switch name {
+				case "", "omitempty", "required", "dive":
+					continue
+				}
+				if _, ok := lookupRule(name); !ok {
+					*errs = append(*errs, fmt.Errorf("%w '%s' on %s.%s", ErrUnknownRule, name, t, sf.Name))
+				}
+			}
+		}
+		walkTags(sf.Type, seen, errs)
+	}
+}
+
+// Validate check v against its `validate` struct tags, nested structs, slices and maps are walked recursively.
+// Returned error is ValidationErrors, error wrapping ErrUnknownRule when tag is invalid, or nil.
+func Validate(v any) error {
+	if err := ValidateTags(v); err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), errs)
+		}
+	}
+}
+
+func validateStruct(v reflect.Value, path string, errs *ValidationErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := inputName(sf)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			validateValue(fv, path, errs)
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			if !validateField(fv, fieldPath, strings.Split(tag, ","), errs) {
+				continue
+			}
+		}
+		validateValue(fv, fieldPath, errs)
+	}
+}
+
+// fieldName resolve field name from json tag, empty name on embedded struct means flatten.
+func fieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" && !sf.Anonymous {
+		name = sf.Name
+	}
+	return name, true
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// validateField apply tag rules, return false when nested validation should be skipped.
+func validateField(v reflect.Value, path string, tags []string, errs *ValidationErrors) bool {
+	for i, tag := range tags {
+		name, param, _ := strings.Cut(strings.TrimSpace(tag), "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			if v.IsZero() {
+				return false
+			}
+			continue
+		case "required":
+			if isEmpty(v) {
+				*errs = append(*errs, newFieldError(path, "required", "", nil, "{field} is required"))
+				return false
+			}
+			continue
+		case "dive":
+			elem := indirect(v)
+			switch elem.Kind() {
+			case reflect.Slice, reflect.Array:
+				for j := 0; j < elem.Len(); j++ {
+					validateField(elem.Index(j), fmt.Sprintf("%s[%d]", path, j), tags[i+1:], errs)
+				}
+			case reflect.Map:
+				iter := elem.MapRange()
+				for iter.Next() {
+					validateField(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), tags[i+1:], errs)
+				}
+			}
+			return true
+		}
+
+		rl, ok := lookupRule(name)
+		elem := indirect(v)
+		if !ok || !elem.IsValid() {
+			continue
+		}
+		if !rl.check(elem, param) {
+			*errs = append(*errs, newFieldError(path, name, param, elem.Interface(), rl.message))
+		}
+	}
+	return true
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isEmpty(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func newFieldError(path, rule, param string, value any, message string) *FieldError {
+	message = strings.NewReplacer("{field}", path, "{param}", param).Replace(message)
+	return &FieldError{
+		Field:   path,
+		Rule:    rule,
+		Param:   param,
+		Value:   value,
+		Message: message,
+	}
+}
+
+// bindErrors convert json decoding error of params into ValidationErrors when possible.
+func bindErrors(err error, params Params) (ValidationErrors, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = typeErr.Struct
+		}
+		value, _ := valueAt(params, field)
+		return ValidationErrors{newFieldError(field, "type", typeErr.Type.String(), value, "{field} must be of type {param}")}, true
+	}
+	return nil, false
+}
+
+// valueAt find value of dotted json field path in params, keys are matched case-insensitively like encoding/json.
+func valueAt(params Params, path string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(params)
+	for _, segment := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = node[segment]; ok {
+			continue
+		}
+		for k, v := range node {
+			if strings.EqualFold(k, segment) {
+				current, ok = v, true
+				break
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// validateBound merge decoding error of params and struct validation into single error.
+func validateBound(en any, params Params, err error) error {
+	var errs ValidationErrors
+	if err != nil {
+		var ok bool
+		if errs, ok = err.(ValidationErrors); !ok {
+			if errs, ok = bindErrors(err, params); !ok {
+				return err
+			}
+		}
+	}
+	if verr := Validate(en); verr != nil {
+		verrs, ok := verr.(ValidationErrors)
+		if !ok {
+			return verr
+		}
+		for _, fe := range verrs {
+			if !errs.has(fe.Field) {
+				errs = append(errs, fe)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v ValidationErrors) has(field string) bool {
+	for _, e := range v {
+		if e.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package jumper
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type typoTagged struct {
+	Name  string `json:"name" validate:"required,mn=3"`
+	Inner struct {
+		Code string `json:"code" validate:"lenn=2"`
+	} `json:"inner"`
+}
+
+func TestValidateUnknownRule(t *testing.T) {
+	err := Validate(typoTagged{Name: "x"})
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Fatalf("Validate() = %v, want ErrUnknownRule", err)
+	}
+	for _, want := range []string{"'mn' on jumper.typoTagged.Name", "'lenn' on"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %q, want it to mention %q", err, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustValidateTags() did not panic")
+		}
+	}()
+	MustValidateTags(&typoTagged{})
+}
+
+func TestParseToUnknownRule(t *testing.T) {
+	req, err := ParseRequest(httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"abc"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseTo[typoTagged](req); !errors.Is(err, ErrUnknownRule) {
+		t.Fatalf("ParseTo() = %v, want ErrUnknownRule", err)
+	}
+}
+
+func TestParseToTypeErrorValue(t *testing.T) {
+	type payload struct {
+		Age int `json:"age"`
+	}
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"age":"ten"}`))
+	r.Header.Set("Content-Type", "application/json")
+	req, err := ParseRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseTo[payload](req)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("ParseTo() = %v, want one ValidationError", err)
+	}
+	if errs[0].Field != "age" || errs[0].Rule != "type" || errs[0].Value != "ten" {
+		t.Errorf("FieldError = %+v, want field age, rule type and value \"ten\"", *errs[0])
+	}
+}
+
+func TestFieldErrorValueHidden(t *testing.T) {
+	defer func(expose bool) { ExposeFieldValues = expose }(ExposeFieldValues)
+	errs := ValidationErrors{newFieldError("password", "min", "12", "hunter2", "{field} must be at least {param}")}
+
+	w := httptest.NewRecorder()
+	if err := PlugResponseFor(w, nil).ReplyValidation(errs); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(w.Body.String(), "hunter2") {
+		t.Errorf("reply = %s, want value hidden", w.Body)
+	}
+
+	ExposeFieldValues = true
+	w = httptest.NewRecorder()
+	PlugResponseFor(w, nil).ReplyValidation(errs)
+	if !strings.Contains(w.Body.String(), `"value":"hunter2"`) {
+		t.Errorf("reply = %s, want value exposed", w.Body)
+	}
+}
+
+func TestValidateDecimal(t *testing.T) {
+	type payment struct {
+		Amount Decimal     `json:"amount" validate:"min=0,lt=100.005"`
+		Fee    *Decimal    `json:"fee" validate:"omitempty,gte=0.01"`
+		Total  ExactNumber `json:"total" validate:"max=1e3"`
+	}
+	fee := MustParseDecimal("0.01")
+	if err := Validate(payment{Amount: MustParseDecimal("12.50"), Fee: &fee, Total: "999.99"}); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+
+	fee = MustParseDecimal("0.009")
+	err := Validate(payment{Amount: MustParseDecimal("100.005"), Fee: &fee, Total: "1000.01"})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate(invalid) = %v, want ValidationErrors", err)
+	}
+	got := map[string]string{}
+	for _, e := range errs {
+		got[e.Field] = e.Rule
+	}
+	if len(got) != 3 || got["amount"] != "lt" || got["fee"] != "gte" || got["total"] != "max" {
+		t.Errorf("Validate(invalid) errors = %v", got)
+	}
+}
+
+func TestReplyValidationHidesOtherErrors(t *testing.T) {
+	for err, status := range map[error]int{
+		checkTags(reflect.TypeOf(typoTagged{})):                          500,
+		&RequestError{Kind: ErrMalformedJSON, Err: errors.New("secret")}: 400,
+		errors.New("pq: password authentication failed"):                 500,
+	} {
+		w := httptest.NewRecorder()
+		if e := PlugResponseFor(w, nil).ReplyValidation(err); e != nil {
+			t.Fatal(e)
+		}
+		if w.Code != status {
+			t.Errorf("ReplyValidation(%v) status = %d, want %d", err, w.Code, status)
+		}
+		for _, leak := range []string{"typoTagged", "secret", "password"} {
+			if strings.Contains(w.Body.String(), leak) {
+				t.Errorf("ReplyValidation(%v) reply = %s, leak %q", err, w.Body, leak)
+			}
+		}
+	}
+}